@@ -4,11 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
 	"uptime_w33d/internal/api"
 	"uptime_w33d/internal/config"
+	"uptime_w33d/internal/probe"
 	"uptime_w33d/internal/repository"
 	"uptime_w33d/internal/scheduler"
 	"uptime_w33d/internal/services"
@@ -76,9 +78,23 @@ func main() {
 	notifySvc := services.NewNotificationService(subRepo)
 
 	sched := scheduler.NewScheduler(monitorRepo, resultRepo, notifySvc)
+	// Exec monitors run local commands, only when the config allows it
+	if cfg.Exec.Enabled && len(cfg.Exec.AllowedCommands) == 0 {
+		logger.Log.Warn("Exec monitors are enabled but exec.allowed_commands is empty, no command will run")
+	}
+	sched.RegisterProbe(probe.NewExecProbe(cfg.Exec.Enabled, cfg.Exec.AllowedCommands, cfg.Exec.AllowedEnv))
 	sched.Start()
 	defer sched.Stop()
 
+	// Docker label discovery
+	if cfg.Docker.Discovery {
+		discovery := services.NewDockerDiscoveryService(monitorRepo, cfg.Docker.Host, cfg.Docker.Label)
+		stopDiscovery := make(chan struct{})
+		defer close(stopDiscovery)
+		go discovery.Run(time.Duration(cfg.Docker.Interval)*time.Second, stopDiscovery)
+		logger.Log.Info("Docker discovery enabled", zap.String("label", cfg.Docker.Label))
+	}
+
 	// 6. Setup Router & Start Server
 	r := api.SetupRouter(cfg, repository.DB)
 	
@@ -64,6 +64,8 @@ func (h *BadgeHandler) GetStatusBadge(c *gin.Context) {
 	if status == "up" {
 		status = "up"
 		color = "#4c1" // green
+	} else if status == "degraded" {
+		color = "#dfb317" // yellow
 	} else if status == "down" {
 		status = "down"
 		color = "#e05d44" // red
@@ -7,16 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 	
 	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/repository"
 	"uptime_w33d/internal/services"
 	"uptime_w33d/pkg/cache"
+	"uptime_w33d/pkg/utils"
 )
 
 type MonitorHandler struct {
 	monitorService services.MonitorService
+	resultRepo     repository.CheckResultRepository
 }
 
-func NewMonitorHandler(monitorService services.MonitorService) *MonitorHandler {
-	return &MonitorHandler{monitorService: monitorService}
+func NewMonitorHandler(monitorService services.MonitorService, resultRepo repository.CheckResultRepository) *MonitorHandler {
+	return &MonitorHandler{monitorService: monitorService, resultRepo: resultRepo}
 }
 
 func (h *MonitorHandler) Create(c *gin.Context) {
@@ -25,6 +28,9 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !allowMonitorType(c, monitor.Type) {
+		return
+	}
 
 	if err := h.monitorService.CreateMonitor(&monitor); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -34,6 +40,7 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 	// Invalidate Cache
 	_ = cache.Delete("public_status_page_default")
 
+	redactSecrets(&monitor)
 	c.JSON(http.StatusCreated, monitor)
 }
 
@@ -55,6 +62,7 @@ func (h *MonitorHandler) Get(c *gin.Context) {
 		return
 	}
 
+	redactSecrets(monitor)
 	c.JSON(http.StatusOK, monitor)
 }
 
@@ -67,6 +75,9 @@ func (h *MonitorHandler) List(c *gin.Context) {
 		return
 	}
 
+	for i := range monitors {
+		redactSecrets(&monitors[i])
+	}
 	c.JSON(http.StatusOK, monitors)
 }
 
@@ -83,6 +94,18 @@ func (h *MonitorHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	existing, err := h.monitorService.GetMonitor(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		return
+	}
+	if !allowMonitorType(c, existing.Type) || !allowMonitorType(c, monitor.Type) {
+		return
+	}
 
 	if err := h.monitorService.UpdateMonitor(uint(id), &monitor); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -113,3 +136,87 @@ func (h *MonitorHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Monitor deleted successfully"})
 }
+
+func (h *MonitorHandler) Acknowledge(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.monitorService.AcknowledgeMonitor(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Monitor acknowledged"})
+}
+
+// History returns the latest check results with their probe details
+// (assertion report, timings, output), unlike the public status page history.
+func (h *MonitorHandler) History(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	monitor, err := h.monitorService.GetMonitor(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitor"})
+		return
+	}
+	if monitor == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		return
+	}
+
+	history, err := h.resultRepo.GetHistory(uint(id), 50) // Limit 50
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *MonitorHandler) RepinHostKey(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.monitorService.RepinHostKey(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Host key will be pinned on the next check"})
+}
+
+// allowMonitorType rejects exec monitors for non-admins, they run commands
+// on this host. It writes the 403 response itself.
+func allowMonitorType(c *gin.Context, t models.MonitorType) bool {
+	if t != models.TypeExec {
+		return true
+	}
+	if role, _ := c.Get("role"); role == string(models.RoleAdmin) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can manage exec monitors"})
+	return false
+}
+
+// redactSecrets clears probe credentials before a monitor is returned.
+// They are write-only: UpdateMonitor keeps the stored value when the
+// client does not send them again, clear_password / clear_private_key drop it.
+// A password in the Target DSN is masked, sending it back keeps the stored one.
+func redactSecrets(m *models.Monitor) {
+	m.Password = ""
+	m.PrivateKey = ""
+	m.Target = utils.RedactTarget(m.Target)
+}
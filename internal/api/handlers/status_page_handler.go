@@ -37,6 +37,7 @@ type PublicMonitorStatus struct {
 	LastStatus        string     `json:"last_status"`
 	LastCheckedAt     *time.Time `json:"last_checked_at"`
 	CertificateExpiry *time.Time `json:"certificate_expiry,omitempty"`
+	DomainExpiry      *time.Time `json:"domain_expiry,omitempty"`
 	Uptime24h         float64    `json:"uptime_24h"` 
 	GroupName         string     `json:"group_name,omitempty"`
 }
@@ -161,14 +162,20 @@ func (h *StatusPageHandler) GetStatus(c *gin.Context) {
 				LastStatus:        m.LastStatus,
 				LastCheckedAt:     m.LastCheckedAt,
 				CertificateExpiry: m.CertificateExpiry,
-			Uptime24h:         uptime,
-			GroupName:         func() string { if m.Group != nil { return m.Group.Name }; return "" }(),
-		})
+				DomainExpiry:      m.DomainExpiry,
+				Uptime24h:         uptime,
+				GroupName:         func() string { if m.Group != nil { return m.Group.Name }; return "" }(),
+			})
+		}
 	}
-}
+
+	// Monitors are only exposed through PublicMonitorStatus, the raw
+	// models carry probe configuration and credentials.
+	config := *page
+	config.Monitors = nil
 
 	response := gin.H{
-		"config":        page,
+		"config":        config,
 		"system_status": "All Systems Operational", // Should calculate real status
 		"monitors":      publicStatus,
 		"cached_at":     time.Now(),
@@ -192,9 +199,25 @@ func (h *StatusPageHandler) GetMonitorHistory(c *gin.Context) {
 		return
 	}
 
-	// Check if monitor exists and is public (Optional but recommended)
-	// monitor, err := h.monitorRepo.GetByID(uint(id))
-	// if err != nil || !monitor.IsPublic { ... }
+	// Only monitors shown on a status page have a public history
+	monitor, err := h.monitorRepo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitor"})
+		return
+	}
+	if monitor == nil || !monitor.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		return
+	}
+	public, err := h.statusSvc.IsMonitorPublic(monitor)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitor"})
+		return
+	}
+	if !public {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		return
+	}
 
 	history, err := h.resultRepo.GetHistory(uint(id), 50) // Limit 50
 	if err != nil {
@@ -202,5 +225,11 @@ func (h *StatusPageHandler) GetMonitorHistory(c *gin.Context) {
 		return
 	}
 
+	// Probe details (command output, traces, assertion actuals) stay private,
+	// the authenticated /monitors/:id/history returns them
+	for i := range history {
+		history[i].Data = nil
+	}
+
 	c.JSON(http.StatusOK, history)
 }
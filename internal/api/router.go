@@ -29,7 +29,8 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 // Monitor Routes
 	monitorRepo := repository.NewMonitorRepository(db)
 	monitorService := services.NewMonitorService(monitorRepo)
-	monitorHandler := handlers.NewMonitorHandler(monitorService)
+	resultRepo := repository.NewCheckResultRepository(db)
+	monitorHandler := handlers.NewMonitorHandler(monitorService, resultRepo)
 
 	// Monitor Group Routes
 	groupRepo := repository.NewMonitorGroupRepository(db)
@@ -44,7 +45,6 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	subHandler := handlers.NewSubscriptionHandler(subRepo)
 
 	notifySvc := services.NewNotificationService(subRepo)
-	resultRepo := repository.NewCheckResultRepository(db)
 	
 	pushService := services.NewPushService(monitorRepo, resultRepo, notifySvc)
 	pushHandler := handlers.NewPushHandler(pushService)
@@ -116,8 +116,11 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 				monitors.GET("", monitorHandler.List)
 				monitors.POST("", monitorHandler.Create)
 				monitors.GET("/:id", monitorHandler.Get)
+				monitors.GET("/:id/history", monitorHandler.History)
 				monitors.PUT("/:id", monitorHandler.Update)
 				monitors.DELETE("/:id", monitorHandler.Delete)
+				monitors.POST("/:id/acknowledge", monitorHandler.Acknowledge)
+				monitors.POST("/:id/repin", monitorHandler.RepinHostKey)
 			}
 
 			// Monitor Groups
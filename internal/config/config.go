@@ -13,6 +13,8 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Log      LogConfig      `mapstructure:"log"`
+	Docker   DockerConfig   `mapstructure:"docker"`
+	Exec     ExecConfig     `mapstructure:"exec"`
 }
 
 type ServerConfig struct {
@@ -46,6 +48,22 @@ type LogConfig struct {
 	Encoding string `mapstructure:"encoding"`
 }
 
+// DockerConfig controls label based auto-discovery of container monitors.
+type DockerConfig struct {
+	Discovery bool   `mapstructure:"discovery"`
+	Host      string `mapstructure:"host"`     // "local" or tcp://host:2375
+	Label     string `mapstructure:"label"`    // e.g. uptime.monitor=true
+	Interval  int    `mapstructure:"interval"` // Seconds between scans
+}
+
+// ExecConfig gates the exec monitor type, which runs commands on this host.
+// Only admins can create or edit exec monitors.
+type ExecConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	AllowedCommands []string `mapstructure:"allowed_commands"` // Absolute paths, empty = none
+	AllowedEnv      []string `mapstructure:"allowed_env"`      // Environment keys monitors may set
+}
+
 func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigFile(path)
 	viper.AutomaticEnv()
@@ -70,6 +88,13 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.encoding", "json")
 
+	viper.SetDefault("docker.discovery", false)
+	viper.SetDefault("docker.host", "local")
+	viper.SetDefault("docker.label", "uptime.monitor=true")
+	viper.SetDefault("docker.interval", 60)
+
+	viper.SetDefault("exec.enabled", false)
+
 	viper.SetDefault("jwt.secret", "changeme")
 	viper.SetDefault("jwt.expiry", 24)
 
@@ -30,13 +30,31 @@ const (
 	TypeHTTP      MonitorType = "http"
 	TypeHTTPKeyword MonitorType = "http_keyword"
 	TypeHTTPJson    MonitorType = "http_json"
+	TypeHTTPSteps   MonitorType = "http_steps"
 	TypeTCP       MonitorType = "tcp"
+	TypeUDP       MonitorType = "udp"
 	TypeWS        MonitorType = "ws"
 	TypePing      MonitorType = "ping"
 	TypeDNS       MonitorType = "dns"
 	TypePush      MonitorType = "push"
 	TypeSteam     MonitorType = "steam"
 	TypeDocker    MonitorType = "docker"
+	TypeGRPC      MonitorType = "grpc"
+	TypePostgres  MonitorType = "postgres"
+	TypeMySQL     MonitorType = "mysql"
+	TypeRedis     MonitorType = "redis"
+	TypeSMTP      MonitorType = "smtp"
+	TypeIMAP      MonitorType = "imap"
+	TypePOP3      MonitorType = "pop3"
+	TypeSSH       MonitorType = "ssh"
+	TypeMQTT      MonitorType = "mqtt"
+	TypeMinecraft MonitorType = "minecraft"
+	TypeExec      MonitorType = "exec"
+	TypePrometheus MonitorType = "prometheus"
+	TypeGraphQL   MonitorType = "graphql"
+	TypeDomain    MonitorType = "domain"
+	TypeNTP       MonitorType = "ntp"
+	TypeLDAP      MonitorType = "ldap"
 )
 
 type Monitor struct {
@@ -52,16 +70,83 @@ type Monitor struct {
 	Headers        string         `gorm:"type:text" json:"headers"`        // JSON string
 	Body           string         `gorm:"type:text" json:"body"`           // Request body
 	Keyword        string         `json:"keyword"`                         // For TypeHTTPKeyword
+	InvertKeyword  bool           `json:"invert_keyword"`                  // Fail if Keyword IS found
 	JSONPath       string         `json:"json_path"`                       // For TypeHTTPJson (e.g. "status")
 	JSONValue      string         `json:"json_value"`                      // Expected value for JSONPath
-	ExpectedStatus string         `json:"expected_status"`                 // e.g. "200", "2xx"
+	ExpectedStatus string         `json:"expected_status"`                 // e.g. "200", "2xx" (gRPC: "SERVING")
+	MaxRedirects   *int           `json:"max_redirects"`                   // nil = default (10), 0 = don't follow
+	ExpectedLocation string       `json:"expected_location"`               // Expected Location header for 3xx
+	Proxy          string         `json:"proxy"`                           // http://, https:// or socks5:// proxy URL
+	HTTPVersion    string         `json:"http_version"`                    // "" (1.1), "1.1", "auto" (negotiate), "2"
+	IPVersion      string         `json:"ip_version"`                      // "", "4", "6"
+	Assertions     string         `gorm:"type:text" json:"assertions"`     // JSON list of probe.Assertion
+	MaxBodySize    int            `json:"max_body_size"`                   // Bytes, 0 = default (1 MiB)
+	DetectChanges  bool           `json:"detect_changes"`                  // Alert when the content hash changes
+	HashSelector   string         `json:"hash_selector"`                   // Optional CSS selector to hash instead of the whole body
+	ContentHash    string         `json:"content_hash"`                    // Last seen content hash (managed by scheduler)
+	Steps          string         `gorm:"type:text" json:"steps"`          // JSON list of probe.HTTPStep for TypeHTTPSteps
+	SendPayload    string         `gorm:"type:text" json:"send_payload"`   // TCP: data to send after connecting
+	PayloadFormat  string         `json:"payload_format"`                  // "text" (default) or "hex"
+	ExpectResponse string         `gorm:"type:text" json:"expect_response"` // Expected reply
+	ExpectMode     string         `json:"expect_mode"`                     // "contains" (default), "regex", "hex"
+	ReadTimeout    int            `json:"read_timeout"`                    // Seconds to wait for the reply, 0 = Timeout
+	UseTLS         bool           `json:"use_tls"`                         // Connect over TLS (non-HTTP probes)
+	VerifyTLS      bool           `json:"verify_tls"`                      // Verify the server certificate chain
+	StartTLS       bool           `json:"start_tls"`                       // Upgrade a plain connection with STARTTLS
+	GRPCService    string         `json:"grpc_service"`                    // gRPC health service name, empty = whole server
+	Username       string         `json:"username"`                        // Credentials for DB/protocol probes
+	Password       string         `json:"password,omitempty"`
+	PrivateKey     string         `gorm:"type:text" json:"private_key,omitempty"` // SSH private key (PEM), Password is its passphrase
+	ClearPassword  bool           `gorm:"-" json:"clear_password,omitempty"`    // Update only: drop the stored Password
+	ClearPrivateKey bool          `gorm:"-" json:"clear_private_key,omitempty"` // Update only: drop the stored PrivateKey
+	HostKeyFingerprint string     `json:"host_key_fingerprint"`            // Pinned SSH host key (SHA256:...), empty = pin first seen
+	Query          string         `gorm:"type:text" json:"query"`          // SQL query, Redis command, metric expression, GraphQL query or LDAP filter
+	ExpectedValue  string         `json:"expected_value"`                  // Expected first value returned by Query (MQTT: retained message)
+	Topic          string         `json:"topic"`                           // MQTT topic
+	Subprotocols   string         `json:"subprotocols"`                    // WebSocket subprotocols, comma separated
+	Container      string         `json:"container"`                       // Docker container name or ID
+	RestartCount   *int           `json:"restart_count"`                   // Last seen container restart count (managed by scheduler)
+	AutoDiscovered bool           `json:"auto_discovered"`                 // Created by Docker label discovery
+	PingCount      int            `json:"ping_count"`                      // Echo requests per check, 0 = 3
+	PingInterval   int            `json:"ping_interval"`                   // Milliseconds between requests, 0 = 1000
+	PacketSize     int            `json:"packet_size"`                     // ICMP payload bytes, 0 = 24 (minimum)
+	DegradedLoss   float64        `json:"degraded_loss"`                   // Packet loss % marking the monitor degraded, 0 = off
+	DownLoss       float64        `json:"down_loss"`                       // Packet loss % marking the monitor down, 0 = 100
+	DegradedJitter int            `json:"degraded_jitter"`                 // Jitter (ms) marking the monitor degraded, 0 = off
+	DownJitter     int            `json:"down_jitter"`                     // Jitter (ms) marking the monitor down, 0 = off
+	Traceroute     string         `json:"traceroute"`                      // Trace the path when going down: "", "icmp", "udp", "tcp" (ping/TCP/HTTP only)
+	MinPlayers     int            `json:"min_players"`                     // Game servers: fail below this many players, 0 = off
+	MaxPlayers     int            `json:"max_players"`                     // Game servers: fail above this many players, 0 = off
+	ExpectedMap    string         `json:"expected_map"`                    // Required map name
+	ServerNamePattern string      `json:"server_name_pattern"`             // Regex the server name (Minecraft: MOTD) must match
+	ExpectPassword *bool          `json:"expect_password"`                 // Required password protection, nil = don't care
+	ExpectVAC      *bool          `json:"expect_vac"`                      // Required VAC status, nil = don't care
+	QueryPlayers   bool           `json:"query_players"`                   // Store the player list in the result
+	QueryRules     bool           `json:"query_rules"`                     // Store the server rules in the result
+	FullAlertAfter int            `json:"full_alert_after"`                // Minutes full before marking degraded, 0 = off
+	EmptyAlertAfter int           `json:"empty_alert_after"`               // Minutes empty before marking degraded, 0 = off
+	PopulationState string        `json:"population_state"`                // "full", "empty" or "" (managed by scheduler)
+	PopulationSince *time.Time    `json:"population_since"`                // When PopulationState was entered (managed by scheduler)
+	Edition        string         `json:"edition"`                         // Minecraft: "java" (default) or "bedrock"
+	Arguments      string         `gorm:"type:text" json:"arguments"`     // Exec: JSON list of command arguments
+	Environment    string         `gorm:"type:text" json:"environment"`   // Exec: JSON object of extra environment variables
+	WorkingDir     string         `json:"working_dir"`                     // Exec: working directory, empty = server's
+	Variables      string         `gorm:"type:text" json:"variables"`     // GraphQL: JSON object of query variables
+	RDAPServer     string         `json:"rdap_server"`                     // Domain: RDAP base URL, empty = IANA bootstrap
+	WHOISServer    string         `json:"whois_server"`                    // Domain: WHOIS host[:port] fallback, empty = IANA referral
+	ExpiryWarnDays int            `json:"expiry_warn_days"`                // Domain: degraded this many days before expiry, 0 = 30
+	ExpiryCriticalDays int        `json:"expiry_critical_days"`            // Domain: down this many days before expiry, 0 = 7
+	MaxOffset      int            `json:"max_offset"`                      // NTP: max clock offset in ms, 0 = 1000
+	SearchBase     string         `json:"search_base"`                     // LDAP: base DN to search, empty = bind only
+	MinEntries     int            `json:"min_entries"`                     // LDAP: minimum number of search results
 	IsPublic       bool           `gorm:"default:false" json:"is_public"`
 	Enabled        bool           `gorm:"default:true" json:"enabled"`
 	GroupID        *uint          `json:"group_id"`
 	Group          *MonitorGroup  `json:"group,omitempty"`
-	LastStatus     string         `json:"last_status"` // "up", "down", "unknown"
+	LastStatus     string         `json:"last_status"` // "up", "degraded", "down", "unknown"
 	LastCheckedAt  *time.Time     `json:"last_checked_at"`
 	CertificateExpiry *time.Time  `json:"certificate_expiry"` // SSL Expiry Date
+	DomainExpiry   *time.Time     `json:"domain_expiry"`      // Domain registration expiry
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -79,9 +164,17 @@ type MonitorGroup struct {
 type CheckResult struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	MonitorID    uint      `gorm:"index;not null" json:"monitor_id"`
-	Status       string    `gorm:"not null" json:"status"` // "up", "down"
+	Status       string    `gorm:"not null" json:"status"` // "up", "degraded", "down"
 	ResponseTime int64     `json:"response_time"`          // ms
 	Message      string    `json:"message"`
+	// HTTP timing breakdown (ms), only set for HTTP monitors
+	DNSTime      int64     `json:"dns_time,omitempty"`
+	ConnectTime  int64     `json:"connect_time,omitempty"`
+	TLSTime      int64     `json:"tls_time,omitempty"`
+	TTFBTime     int64     `json:"ttfb_time,omitempty"`
+	TransferTime int64     `json:"transfer_time,omitempty"`
+	// Probe specific details, e.g. the assertion report
+	Data         map[string]interface{} `gorm:"type:text;serializer:json" json:"data,omitempty"`
 	CreatedAt    time.Time `gorm:"index" json:"created_at"`
 }
 
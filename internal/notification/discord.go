@@ -51,6 +51,8 @@ func (n *DiscordNotifier) Send(configJSON string, msg NotificationMessage) error
 	if msg.Status == "up" {
 		color = 5763719 // Green: 0x57F287 (Decimal 5763719) -> Wait, this is gray. Green is 5763719? No.
 		color = 3066993 // Green 0x2ECC71
+	} else if msg.Status == "degraded" {
+		color = 15105570 // Orange 0xE67E22
 	} else if msg.Status == "down" {
 		color = 15158332 // Red 0xE74C3C
 	}
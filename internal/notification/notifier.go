@@ -3,7 +3,7 @@ package notification
 type NotificationMessage struct {
 	MonitorName string
 	Target      string
-	Status      string // "up" or "down"
+	Status      string // "up", "degraded" or "down"
 	Message     string
 	Time        string
 }
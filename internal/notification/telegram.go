@@ -38,6 +38,8 @@ func (n *TelegramNotifier) Send(configJSON string, msg NotificationMessage) erro
 	icon := "❓"
 	if msg.Status == "up" {
 		icon = "✅"
+	} else if msg.Status == "degraded" {
+		icon = "⚠️"
 	} else if msg.Status == "down" {
 		icon = "🔴"
 	}
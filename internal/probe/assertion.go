@@ -0,0 +1,288 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Assertion is a single response check configured on a monitor.
+//
+// Source selects what is inspected:
+//   - "status": the status code
+//   - "header": the header named by Property
+//   - "body":   the raw response body
+//   - "json":   the GJSON path in Property, evaluated against the body
+//   - "size":   the body size in bytes (not evaluable once the body is truncated)
+//   - "css":    the first HTML element matching the CSS selector in Property
+//   - "xpath":  the first HTML/XML node matching the XPath in Property
+//
+// For "css" and "xpath" the value is the element text, or the attribute
+// named by Attribute when set; "exists" checks that the element is present.
+//
+// Operator is one of ==, !=, <, <=, >, >=, contains, not_contains, regex,
+// not_regex, exists, not_exists.
+type Assertion struct {
+	Source    string `json:"source"`
+	Property  string `json:"property,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Operator  string `json:"operator"`
+	Target    string `json:"target,omitempty"`
+}
+
+// AssertionResult is one line of the assertion report stored in Result.Data["assertions"].
+type AssertionResult struct {
+	Assertion
+	Passed  bool   `json:"passed"`
+	Actual  string `json:"actual,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ParseAssertions decodes the monitor's JSON assertion list.
+func ParseAssertions(raw string) ([]Assertion, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var assertions []Assertion
+	if err := json.Unmarshal([]byte(raw), &assertions); err != nil {
+		return nil, fmt.Errorf("invalid assertions: %w", err)
+	}
+	return assertions, nil
+}
+
+// EvaluateAssertions runs every assertion against the response and returns the full report.
+// The second return value is the first failing assertion, or nil when all passed.
+// truncated tells that body was cut at the read limit: size assertions then fail
+// as not evaluable and other body failures mention the cut.
+func EvaluateAssertions(assertions []Assertion, resp *http.Response, body []byte, truncated bool) ([]AssertionResult, *AssertionResult) {
+	report := make([]AssertionResult, 0, len(assertions))
+	failed := -1
+	for _, a := range assertions {
+		r := evaluateAssertion(a, resp, body)
+		if truncated && a.Source != "status" && a.Source != "header" {
+			if a.Source == "size" {
+				r.Passed = false
+				r.Actual = ""
+				r.Message = fmt.Sprintf("size not evaluable, body truncated at %d bytes (raise max_body_size)", len(body))
+			} else if !r.Passed {
+				r.Message += fmt.Sprintf(" (body truncated at %d bytes)", len(body))
+			}
+		}
+		report = append(report, r)
+		if !r.Passed && failed < 0 {
+			failed = len(report) - 1
+		}
+	}
+	if failed < 0 {
+		return report, nil
+	}
+	return report, &report[failed]
+}
+
+func evaluateAssertion(a Assertion, resp *http.Response, body []byte) AssertionResult {
+	r := AssertionResult{Assertion: a}
+
+	var actual string
+	exists := true
+	switch a.Source {
+	case "status":
+		actual = strconv.Itoa(resp.StatusCode)
+	case "header":
+		values, ok := resp.Header[http.CanonicalHeaderKey(a.Property)]
+		exists = ok
+		actual = strings.Join(values, ", ")
+	case "body":
+		actual = string(body)
+	case "json":
+		res := gjson.GetBytes(body, a.Property)
+		exists = res.Exists()
+		actual = res.String()
+		// For arrays, "contains" means one of the elements equals the target
+		if a.Operator == "contains" && res.IsArray() {
+			r.Actual = truncate(actual, 200)
+			for _, item := range res.Array() {
+				if item.String() == a.Target {
+					r.Passed = true
+					return r
+				}
+			}
+			r.Message = fmt.Sprintf("json %s does not contain '%s'", a.Property, a.Target)
+			return r
+		}
+	case "size":
+		actual = strconv.Itoa(len(body))
+	case "css", "xpath":
+		var err error
+		actual, exists, err = queryMarkup(a, resp, body)
+		if err != nil {
+			r.Message = err.Error()
+			return r
+		}
+	default:
+		r.Message = fmt.Sprintf("unknown assertion source '%s'", a.Source)
+		return r
+	}
+
+	// The body itself is too large for the report
+	if a.Source != "body" {
+		r.Actual = truncate(actual, 200)
+	}
+
+	passed, err := compare(a.Operator, actual, a.Target, exists)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	r.Passed = passed
+	if !passed {
+		r.Message = describeFailure(a, r.Actual, exists)
+	}
+	return r
+}
+
+// compare applies the operator to the actual value. Ordering operators
+// compare numerically; == and != fall back to string comparison.
+func compare(op, actual, target string, exists bool) (bool, error) {
+	switch op {
+	case "exists":
+		return exists, nil
+	case "not_exists":
+		return !exists, nil
+	}
+	if !exists {
+		return false, nil
+	}
+
+	switch op {
+	case "==", "equals":
+		if a, t, ok := parseNumbers(actual, target); ok {
+			return a == t, nil
+		}
+		return actual == target, nil
+	case "!=", "not_equals":
+		if a, t, ok := parseNumbers(actual, target); ok {
+			return a != t, nil
+		}
+		return actual != target, nil
+	case "<", "<=", ">", ">=":
+		a, t, ok := parseNumbers(actual, target)
+		if !ok {
+			return false, fmt.Errorf("operator %s needs numeric values, got '%s' and '%s'", op, truncate(actual, 50), target)
+		}
+		switch op {
+		case "<":
+			return a < t, nil
+		case "<=":
+			return a <= t, nil
+		case ">":
+			return a > t, nil
+		default:
+			return a >= t, nil
+		}
+	case "contains":
+		return strings.Contains(actual, target), nil
+	case "not_contains":
+		return !strings.Contains(actual, target), nil
+	case "regex", "not_regex":
+		re, err := regexp.Compile(target)
+		if err != nil {
+			return false, fmt.Errorf("invalid regex '%s': %v", target, err)
+		}
+		return re.MatchString(actual) == (op == "regex"), nil
+	default:
+		return false, fmt.Errorf("unknown operator '%s'", op)
+	}
+}
+
+func parseNumbers(a, b string) (float64, float64, bool) {
+	x, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func describeFailure(a Assertion, actual string, exists bool) string {
+	subject := a.Source
+	if a.Property != "" {
+		subject = fmt.Sprintf("%s %s", a.Source, a.Property)
+	}
+	if a.Attribute != "" {
+		subject = fmt.Sprintf("%s @%s", subject, a.Attribute)
+	}
+	if !exists && a.Operator != "not_exists" {
+		return fmt.Sprintf("%s not found", subject)
+	}
+	if a.Source == "body" {
+		return fmt.Sprintf("body %s '%s' failed", a.Operator, a.Target)
+	}
+	if a.Operator == "exists" || a.Operator == "not_exists" {
+		return fmt.Sprintf("%s %s failed", subject, a.Operator)
+	}
+	return fmt.Sprintf("%s %s '%s' failed (got '%s')", subject, a.Operator, a.Target, actual)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// StatusMatches checks a status code against an expected status spec:
+// a comma separated list of exact codes ("404"), ranges ("200-299")
+// and classes ("2xx"). An empty spec means "200".
+func StatusMatches(code int, spec string) (bool, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = "200"
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Class, e.g. "2xx"
+		if len(part) == 3 && strings.HasSuffix(strings.ToLower(part), "xx") {
+			class, err := strconv.Atoi(part[:1])
+			if err != nil {
+				return false, fmt.Errorf("invalid status class '%s'", part)
+			}
+			if code/100 == class {
+				return true, nil
+			}
+			continue
+		}
+
+		// Range, e.g. "200-299"
+		if lo, hi, ok := strings.Cut(part, "-"); ok {
+			from, err1 := strconv.Atoi(strings.TrimSpace(lo))
+			to, err2 := strconv.Atoi(strings.TrimSpace(hi))
+			if err1 != nil || err2 != nil || from > to {
+				return false, fmt.Errorf("invalid status range '%s'", part)
+			}
+			if code >= from && code <= to {
+				return true, nil
+			}
+			continue
+		}
+
+		exact, err := strconv.Atoi(part)
+		if err != nil {
+			return false, fmt.Errorf("invalid status code '%s'", part)
+		}
+		if code == exact {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,36 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkValue compares the first column of the first row with the expected value.
+func checkValue(value interface{}, noRows bool, expected string, duration time.Duration) Result {
+	var p BaseProbe
+	got := ""
+	if value != nil {
+		if b, ok := value.([]byte); ok {
+			got = string(b)
+		} else {
+			got = fmt.Sprint(value)
+		}
+	}
+
+	if expected != "" && (noRows || got != expected) {
+		if noRows {
+			got = "no rows"
+		}
+		res := p.RecordResult(false, fmt.Sprintf("Value mismatch: expected '%s', got '%s'", expected, got), duration)
+		res.Data["value"] = got
+		return res
+	}
+
+	msg := "Query OK"
+	if !noRows {
+		msg = fmt.Sprintf("Query OK: %s", truncate(got, 100))
+	}
+	res := p.RecordResult(true, msg, duration)
+	res.Data["value"] = got
+	return res
+}
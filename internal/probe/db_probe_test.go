@@ -0,0 +1,372 @@
+package probe_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jackc/pgx/v5/pgproto3"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func TestRedisProbe_Check(t *testing.T) {
+	mr := miniredis.RunT(t)
+	mr.RequireAuth("s3cret")
+	mr.Set("deploy:version", "42")
+
+	p := probe.NewRedisProbe()
+	m := models.Monitor{
+		Type:     models.TypeRedis,
+		Target:   mr.Addr(),
+		Timeout:  1,
+		Password: "s3cret",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected PING success, got failure: %s", result.Message)
+	}
+	if result.Data["value"] != "PONG" {
+		t.Errorf("Expected PONG, got %v", result.Data["value"])
+	}
+
+	m.Query = "GET deploy:version"
+	m.ExpectedValue = "42"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected value match, got failure: %s", result.Message)
+	}
+
+	m.ExpectedValue = "43"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected value mismatch failure, got success")
+	}
+
+	m.Query = "GET missing"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for missing key with expected value, got success")
+	}
+
+	m.Query = ""
+	m.ExpectedValue = ""
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected auth failure, got success")
+	}
+}
+
+// startPostgresServer speaks just enough of the wire protocol for the probe:
+// cleartext password "s3cret", and every query returns one text row "42"
+// unless it contains "WHERE false". With requireTLS plain connections are
+// dropped.
+func startPostgresServer(t *testing.T, requireTLS bool) string {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsConfig := &tls.Config{Certificates: ts.TLS.Certificates}
+	ts.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer func() { c.Close() }()
+				backend := pgproto3.NewBackend(c, c)
+				startup, err := backend.ReceiveStartupMessage()
+				if err != nil {
+					return
+				}
+				if _, ok := startup.(*pgproto3.SSLRequest); ok {
+					c.Write([]byte("S"))
+					c = tls.Server(c, tlsConfig)
+					backend = pgproto3.NewBackend(c, c)
+					if startup, err = backend.ReceiveStartupMessage(); err != nil {
+						return
+					}
+				} else if requireTLS {
+					return
+				}
+				if _, ok := startup.(*pgproto3.StartupMessage); !ok {
+					return
+				}
+
+				backend.Send(&pgproto3.AuthenticationCleartextPassword{})
+				backend.Flush()
+				backend.SetAuthType(pgproto3.AuthTypeCleartextPassword)
+				msg, err := backend.Receive()
+				if pw, ok := msg.(*pgproto3.PasswordMessage); err != nil || !ok || pw.Password != "s3cret" {
+					backend.Send(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "28P01", Message: "password authentication failed"})
+					backend.Flush()
+					return
+				}
+				backend.Send(&pgproto3.AuthenticationOk{})
+				backend.Send(&pgproto3.ParameterStatus{Name: "server_version", Value: "16.4"})
+				backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+				backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+				backend.Flush()
+
+				columns := &pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{Name: []byte("value"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1}}}
+				var query string
+				rows := func() {
+					if !strings.Contains(query, "WHERE false") {
+						backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte("42")}})
+					}
+					backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+				}
+				for {
+					msg, err := backend.Receive()
+					if err != nil {
+						return
+					}
+					switch m := msg.(type) {
+					case *pgproto3.Query:
+						query = m.String
+						backend.Send(columns)
+						rows()
+						backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+					case *pgproto3.Parse:
+						query = m.Query
+						backend.Send(&pgproto3.ParseComplete{})
+					case *pgproto3.Describe:
+						if m.ObjectType == 'S' {
+							backend.Send(&pgproto3.ParameterDescription{})
+						}
+						backend.Send(columns)
+					case *pgproto3.Bind:
+						backend.Send(&pgproto3.BindComplete{})
+					case *pgproto3.Execute:
+						rows()
+					case *pgproto3.Sync:
+						backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+					case *pgproto3.Terminate:
+						return
+					}
+					backend.Flush()
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestPostgresProbe_Check(t *testing.T) {
+	addr := startPostgresServer(t, false)
+
+	p := probe.NewPostgresProbe()
+	m := models.Monitor{
+		Type:     models.TypePostgres,
+		Target:   addr,
+		Timeout:  2,
+		Username: "monitor",
+		Password: "s3cret",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["value"] != "42" || result.Data["server_version"] != "16.4" {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+
+	m.Query = "SELECT version FROM deploys"
+	m.ExpectedValue = "42"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected value match, got failure: %s", result.Message)
+	}
+	m.ExpectedValue = "43"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected value mismatch failure, got success")
+	}
+	m.Query = "SELECT version FROM deploys WHERE false"
+	m.ExpectedValue = "42"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for no rows with expected value, got success")
+	}
+
+	m.Query, m.ExpectedValue = "", ""
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected auth failure, got success")
+	}
+}
+
+func TestPostgresProbe_Check_TLS(t *testing.T) {
+	addr := startPostgresServer(t, true)
+
+	p := probe.NewPostgresProbe()
+	// The driver default (prefer) negotiates TLS, an explicit disable does not
+	m := models.Monitor{Type: models.TypePostgres, Target: addr, Timeout: 2, Username: "monitor", Password: "s3cret"}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with the default sslmode, got failure: %s", result.Message)
+	}
+	m.Target = "postgres://" + addr + "/?sslmode=disable"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure with sslmode=disable, got success")
+	}
+	m.Target = addr
+
+	m.UseTLS = true
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with sslmode=require, got failure: %s", result.Message)
+	}
+
+	m.VerifyTLS = true
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected certificate verification failure, got success")
+	}
+}
+
+// startMySQLServer speaks just enough of the MySQL protocol for the probe:
+// any credentials are accepted, and every query returns one row "42" unless
+// it contains "WHERE false".
+func startMySQLServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	const (
+		clientLongPassword     = 0x00000001
+		clientProtocol41       = 0x00000200
+		clientTransactions     = 0x00002000
+		clientSecureConnection = 0x00008000
+		clientPluginAuth       = 0x00080000
+	)
+	lenencString := func(s string) []byte { return append([]byte{byte(len(s))}, s...) }
+	eof := []byte{0xfe, 0, 0, 2, 0}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				r := bufio.NewReader(c)
+				write := func(seq byte, payload []byte) {
+					header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}
+					c.Write(append(header, payload...))
+				}
+				read := func() ([]byte, byte, error) {
+					header := make([]byte, 4)
+					if _, err := io.ReadFull(r, header); err != nil {
+						return nil, 0, err
+					}
+					payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+					_, err := io.ReadFull(r, payload)
+					return payload, header[3], err
+				}
+
+				caps := uint32(clientLongPassword | clientProtocol41 | clientTransactions | clientSecureConnection | clientPluginAuth)
+				hello := []byte{10}
+				hello = append(hello, "8.0.36-fake\x00"...)
+				hello = append(hello, 1, 0, 0, 0)        // connection id
+				hello = append(hello, "abcdefgh\x00"...) // auth data part 1 + filler
+				hello = binary.LittleEndian.AppendUint16(hello, uint16(caps))
+				hello = append(hello, 45, 2, 0) // charset, status
+				hello = binary.LittleEndian.AppendUint16(hello, uint16(caps>>16))
+				hello = append(hello, 21)
+				hello = append(hello, make([]byte, 10)...)
+				hello = append(hello, "ijklmnopqrst\x00"...) // auth data part 2
+				hello = append(hello, "mysql_native_password\x00"...)
+				write(0, hello)
+
+				_, seq, err := read()
+				if err != nil {
+					return
+				}
+				write(seq+1, []byte{0, 0, 0, 2, 0, 0, 0}) // OK
+
+				for {
+					packet, _, err := read()
+					if err != nil || len(packet) == 0 || packet[0] == 0x01 { // COM_QUIT
+						return
+					}
+					if packet[0] != 0x03 { // Only COM_QUERY
+						write(1, []byte{0, 0, 0, 2, 0, 0, 0})
+						continue
+					}
+					column := []byte{}
+					for _, f := range []string{"def", "", "", "", "value", ""} {
+						column = append(column, lenencString(f)...)
+					}
+					column = append(column, 0x0c, 45, 0, 255, 0, 0, 0, 0xfd, 0, 0, 0, 0, 0) // VAR_STRING
+					write(1, []byte{1})
+					write(2, column)
+					write(3, eof)
+					seq := byte(4)
+					if !strings.Contains(string(packet[1:]), "WHERE false") {
+						write(seq, lenencString("42"))
+						seq++
+					}
+					write(seq, eof)
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestMySQLProbe_Check(t *testing.T) {
+	addr := startMySQLServer(t)
+
+	p := probe.NewMySQLProbe()
+	m := models.Monitor{Type: models.TypeMySQL, Target: addr, Timeout: 2, Username: "monitor", Password: "s3cret"}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["value"] != "42" {
+		t.Errorf("Expected 42, got %v", result.Data["value"])
+	}
+
+	m.Query = "SELECT version FROM deploys"
+	m.ExpectedValue = "42"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected value match, got failure: %s", result.Message)
+	}
+	m.ExpectedValue = "43"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected value mismatch failure, got success")
+	}
+	m.Query = "SELECT version FROM deploys WHERE false"
+	m.ExpectedValue = "42"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for no rows with expected value, got success")
+	}
+}
+
+func TestDatabaseProbes_ConnectionRefused(t *testing.T) {
+	// Grab a free port and close it so nothing is listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	for _, p := range []probe.Probe{probe.NewPostgresProbe(), probe.NewMySQLProbe(), probe.NewRedisProbe()} {
+		m := models.Monitor{Type: p.Type(), Target: addr, Timeout: 1}
+		if result := p.Check(m); result.Success {
+			t.Errorf("%s: expected connection failure, got success", p.Type())
+		}
+	}
+}
@@ -3,8 +3,10 @@ package probe
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"uptime_w33d/internal/models"
 )
@@ -21,58 +23,94 @@ func (p *DockerProbe) Type() models.MonitorType {
 	return models.TypeDocker
 }
 
+// NewDockerClient connects to the Docker daemon at host ("local" or empty for
+// the default socket, otherwise tcp:// or unix://).
+func NewDockerClient(host string) (*client.Client, error) {
+	return client.NewClientWithOpts(client.WithHost(DockerHost(host)), client.WithAPIVersionNegotiation())
+}
+
+// DockerHost resolves a monitor target to a daemon address, "" and "local"
+// being the local socket.
+func DockerHost(host string) string {
+	if host == "" || host == "local" {
+		return client.DefaultDockerHost
+	}
+	return host
+}
+
+// Check inspects monitor.Container on the daemon in monitor.Target. It fails
+// when the container is not running, its health check reports unhealthy, it
+// was OOM killed, or its restart count went above RestartCount (kept by the
+// scheduler until the restarts are acknowledged).
 func (p *DockerProbe) Check(monitor models.Monitor) Result {
 	start := time.Now()
-	
-	// Use default socket or custom TCP host
-	host := client.DefaultDockerHost
-	if monitor.Target != "" && monitor.Target != "local" {
-		host = monitor.Target // e.g. tcp://192.168.1.100:2375
+
+	containerID := monitor.Container
+	if containerID == "" {
+		// Older monitors kept the container in the keyword field
+		containerID = monitor.Keyword
+	}
+	if containerID == "" {
+		return p.RecordResult(false, "container name/id required", 0)
 	}
 
-	cli, err := client.NewClientWithOpts(client.WithHost(host), client.WithAPIVersionNegotiation())
+	cli, err := NewDockerClient(monitor.Target)
 	if err != nil {
 		return p.RecordResult(false, fmt.Sprintf("failed to create docker client: %v", err), 0)
 	}
 	defer cli.Close()
 
-	// Target format: "container_name" or "container_id"
-	// But monitor.Target is used for HOST. So where do we put Container ID?
-	// We can reuse the "Keyword" field for Container ID/Name, or add a new field.
-	// Let's use "Keyword" field for Container Name/ID for now as a quick win, 
-	// or parse Target like "tcp://host:port/container_name" which is non-standard.
-	
-	// Better approach: 
-	// If Target is "local" or empty -> use local socket.
-	// If Target starts with tcp:// or unix:// -> use that as host.
-	// We need a place for Container ID. Let's use the 'Body' field? No, that's weird.
-	// Let's use 'Keyword' field as 'Container Name/ID'.
-	
-	containerID := monitor.Keyword
-	if containerID == "" {
-		return p.RecordResult(false, "container name/id required (in keyword field)", 0)
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(monitor.Timeout)*time.Second)
 	defer cancel()
 
-	json, err := cli.ContainerInspect(ctx, containerID)
+	info, err := cli.ContainerInspect(ctx, containerID)
 	duration := time.Since(start)
 
 	if err != nil {
 		return p.RecordResult(false, fmt.Sprintf("failed to inspect container: %v", err), duration)
 	}
 
-	success := json.State.Running
-	msg := fmt.Sprintf("Container is %s", json.State.Status)
-	if !success {
-		msg = fmt.Sprintf("Container is not running (status: %s)", json.State.Status)
+	state := info.State
+	health := ""
+	if state.Health != nil {
+		health = string(state.Health.Status)
+	}
+
+	success := true
+	msg := fmt.Sprintf("Container is %s", state.Status)
+	if health != "" {
+		msg = fmt.Sprintf("Container is %s (%s)", state.Status, health)
+	}
+
+	switch {
+	case state.OOMKilled:
+		success = false
+		msg = "Container was OOM killed"
+	case !state.Running:
+		success = false
+		msg = fmt.Sprintf("Container is not running (status: %s, exit code %d)", state.Status, state.ExitCode)
+	case health == container.Unhealthy:
+		success = false
+		msg = "Container is unhealthy"
+		if logs := state.Health.Log; len(logs) > 0 {
+			if out := strings.TrimSpace(logs[len(logs)-1].Output); out != "" {
+				msg += ": " + truncate(out, 100)
+			}
+		}
+	case monitor.RestartCount != nil && info.RestartCount > *monitor.RestartCount:
+		success = false
+		msg = fmt.Sprintf("Container restarted %d time(s) since last acknowledged", info.RestartCount-*monitor.RestartCount)
 	}
 
 	res := p.RecordResult(success, msg, duration)
-	res.Data["state"] = json.State.Status
-	res.Data["image"] = json.Config.Image
-	res.Data["created"] = json.Created
-	
+	res.Data["state"] = state.Status
+	res.Data["image"] = info.Config.Image
+	res.Data["created"] = info.Created
+	res.Data["restart_count"] = info.RestartCount
+	res.Data["oom_killed"] = state.OOMKilled
+	if health != "" {
+		res.Data["health"] = health
+	}
+
 	return res
 }
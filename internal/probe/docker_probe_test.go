@@ -0,0 +1,109 @@
+package probe_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// startDockerAPI fakes the inspect endpoint of a Docker daemon for the given
+// containers (name -> inspect response).
+func startDockerAPI(t *testing.T, containers map[string]map[string]interface{}) string {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.47")
+		if strings.HasSuffix(r.URL.Path, "/_ping") {
+			w.Write([]byte("OK"))
+			return
+		}
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) == 4 && parts[1] == "containers" && parts[3] == "json" {
+			if c, ok := containers[parts[2]]; ok {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(c)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"No such container"}`))
+	}))
+	t.Cleanup(ts.Close)
+	return "tcp://" + strings.TrimPrefix(ts.URL, "http://")
+}
+
+func inspectResponse(status string, running bool, health string, restarts int, oom bool) map[string]interface{} {
+	state := map[string]interface{}{"Status": status, "Running": running, "OOMKilled": oom}
+	if health != "" {
+		state["Health"] = map[string]interface{}{
+			"Status": health,
+			"Log":    []map[string]interface{}{{"ExitCode": 1, "Output": "curl: (7) connection refused\n"}},
+		}
+	}
+	return map[string]interface{}{
+		"Id":           "abc123",
+		"Created":      "2026-01-01T00:00:00Z",
+		"RestartCount": restarts,
+		"State":        state,
+		"Config":       map[string]interface{}{"Image": "nginx:latest"},
+	}
+}
+
+func TestDockerProbe_Check(t *testing.T) {
+	host := startDockerAPI(t, map[string]map[string]interface{}{
+		"web":     inspectResponse("running", true, "healthy", 2, false),
+		"sick":    inspectResponse("running", true, "unhealthy", 0, false),
+		"oom":     inspectResponse("exited", false, "", 0, true),
+		"stopped": inspectResponse("exited", false, "", 0, false),
+		"legacy":  inspectResponse("running", true, "", 0, false),
+	})
+
+	p := probe.NewDockerProbe()
+	m := models.Monitor{Type: models.TypeDocker, Target: host, Container: "web", Timeout: 2}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["restart_count"] != 2 || result.Data["health"] != "healthy" {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+
+	// Same restart count as last time is fine, a higher one is not
+	seen := 2
+	m.RestartCount = &seen
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with unchanged restart count, got failure: %s", result.Message)
+	}
+	seen = 1
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure after a restart, got success")
+	}
+
+	for _, name := range []string{"sick", "oom", "stopped", "missing"} {
+		m := models.Monitor{Type: models.TypeDocker, Target: host, Container: name, Timeout: 2}
+		if result := p.Check(m); result.Success {
+			t.Errorf("%s: expected failure, got success", name)
+		}
+	}
+
+	m = models.Monitor{Type: models.TypeDocker, Target: host, Keyword: "legacy", Timeout: 2}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected keyword fallback to work, got failure: %s", result.Message)
+	}
+}
+
+func TestDockerHost(t *testing.T) {
+	local := probe.DockerHost("")
+	if probe.DockerHost("local") != local {
+		t.Errorf("Expected \"local\" and \"\" to be the same daemon, got %q and %q", probe.DockerHost("local"), local)
+	}
+	if host := probe.DockerHost("tcp://10.0.0.5:2375"); host != "tcp://10.0.0.5:2375" {
+		t.Errorf("Expected remote hosts to be kept, got %q", host)
+	}
+}
@@ -0,0 +1,433 @@
+package probe
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/sync/singleflight"
+
+	"uptime_w33d/internal/models"
+)
+
+const (
+	rdapBootstrapURL     = "https://data.iana.org/rdap/dns.json"
+	rdapBootstrapTTL     = 24 * time.Hour
+	ianaWHOIS            = "whois.iana.org:43"
+	defaultExpiryWarn    = 30 // days
+	defaultExpiryCrit    = 7  // days
+	maxWHOISResponseSize = 256 << 10
+	// Registries rate limit RDAP and WHOIS, expiry dates barely move
+	domainCacheTTL      = 6 * time.Hour
+	domainErrorCacheTTL = 15 * time.Minute
+)
+
+// holdStatuses are EPP status codes meaning the domain does not resolve or is
+// about to be lost.
+var holdStatuses = []string{"clienthold", "serverhold", "redemptionperiod", "pendingdelete"}
+
+// whoisExpiryKeys and whoisDateLayouts cover the common registry formats.
+var (
+	whoisExpiryKeys = []string{
+		"registry expiry date", "registrar registration expiration date",
+		"expiration date", "expiry date", "expire date", "expires", "expires on",
+		"paid-till", "renewal date",
+	}
+	whoisDateLayouts = []string{
+		time.RFC3339, "2006-01-02T15:04:05Z", "2006-01-02T15:04:05", "2006-01-02 15:04:05",
+		"2006-01-02 15:04:05 MST", "2006-01-02", "2006.01.02", "02-Jan-2006", "2006/01/02",
+	}
+)
+
+// rdapBootstrap caches the IANA TLD -> RDAP server map. The lock only
+// guards the map, refreshes run outside it (one at a time via fetch).
+var rdapBootstrap struct {
+	sync.Mutex
+	services map[string]string
+	fetched  time.Time
+	fetch    singleflight.Group
+}
+
+type DomainProbe struct {
+	BaseProbe
+	mu    sync.Mutex
+	cache map[string]domainLookup
+}
+
+// domainLookup is a cached registration lookup.
+type domainLookup struct {
+	info    *domainInfo
+	err     error
+	expires time.Time
+}
+
+func NewDomainProbe() *DomainProbe {
+	return &DomainProbe{cache: make(map[string]domainLookup)}
+}
+
+func (p *DomainProbe) Type() models.MonitorType {
+	return models.TypeDomain
+}
+
+// domainInfo is what we learn about a registration.
+type domainInfo struct {
+	Expiry    time.Time
+	Registrar string
+	Status    []string
+	Source    string
+}
+
+// Check looks up the registration of monitor.Target over RDAP (RDAPServer or
+// the IANA bootstrap), falling back to WHOIS (WHOISServer or the IANA
+// referral). It is degraded ExpiryWarnDays before expiry and down
+// ExpiryCriticalDays before, or when the domain is on hold.
+func (p *DomainProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	// Registries only know the registrable domain, not www.example.co.uk
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(monitor.Target), ".")))
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid domain: %s", monitor.Target), 0)
+	}
+
+	info, err := p.lookup(monitor, domain, timeout)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), time.Since(start))
+	}
+	duration := time.Since(start)
+
+	if info.Expiry.IsZero() {
+		res := p.RecordResult(false, "no expiry date in registration data", duration)
+		res.Data["source"] = info.Source
+		return res
+	}
+
+	warn, crit := monitor.ExpiryWarnDays, monitor.ExpiryCriticalDays
+	if warn <= 0 {
+		warn = defaultExpiryWarn
+	}
+	if crit <= 0 {
+		crit = defaultExpiryCrit
+	}
+	days := int(time.Until(info.Expiry).Hours() / 24)
+
+	success, degraded := true, false
+	msg := fmt.Sprintf("Expires %s (%d days)", info.Expiry.Format("2006-01-02"), days)
+	if info.Registrar != "" {
+		msg += ", registrar " + info.Registrar
+	}
+	switch {
+	case days < 0:
+		success = false
+		msg = fmt.Sprintf("Domain expired on %s", info.Expiry.Format("2006-01-02"))
+	case days < crit:
+		success = false
+		msg = fmt.Sprintf("Domain expires in %d days (%s)", days, info.Expiry.Format("2006-01-02"))
+	case hasHoldStatus(info.Status):
+		success = false
+		msg = fmt.Sprintf("Domain status: %s", strings.Join(info.Status, ", "))
+	case days < warn:
+		degraded = true
+		msg = fmt.Sprintf("Domain expires in %d days (%s)", days, info.Expiry.Format("2006-01-02"))
+	}
+
+	res := p.RecordResult(success, msg, duration)
+	res.Degraded = degraded
+	res.Data["domain_expiry"] = info.Expiry
+	res.Data["days_left"] = days
+	res.Data["registrar"] = info.Registrar
+	res.Data["status"] = info.Status
+	res.Data["source"] = info.Source
+	return res
+}
+
+// lookup returns the cached registration of domain, refreshing it over RDAP
+// (falling back to WHOIS) once it is older than domainCacheTTL.
+func (p *DomainProbe) lookup(monitor models.Monitor, domain string, timeout time.Duration) (*domainInfo, error) {
+	key := domain + "|" + monitor.RDAPServer + "|" + monitor.WHOISServer
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	if ok && !time.Now().Before(cached.expires) {
+		delete(p.cache, key)
+		ok = false
+	}
+	p.mu.Unlock()
+	if ok {
+		return cached.info, cached.err
+	}
+
+	entry := domainLookup{expires: time.Now().Add(domainCacheTTL)}
+	info, rdapErr := lookupRDAP(monitor, domain, timeout)
+	if rdapErr != nil {
+		var err error
+		info, err = lookupWHOIS(monitor, domain, timeout)
+		if err != nil {
+			info = nil
+			entry.err = fmt.Errorf("lookup failed: RDAP: %v; WHOIS: %v", rdapErr, err)
+			entry.expires = time.Now().Add(domainErrorCacheTTL)
+		}
+	}
+	entry.info = info
+
+	// Drop what expired meanwhile so domains no longer checked don't pile up
+	p.mu.Lock()
+	now := time.Now()
+	for k, e := range p.cache {
+		if !now.Before(e.expires) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = entry
+	p.mu.Unlock()
+	return entry.info, entry.err
+}
+
+func hasHoldStatus(statuses []string) bool {
+	for _, s := range statuses {
+		normalized := strings.ToLower(strings.ReplaceAll(s, " ", ""))
+		for _, hold := range holdStatuses {
+			if normalized == hold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupRDAP fetches /domain/<name> from the registry's RDAP service.
+func lookupRDAP(monitor models.Monitor, domain string, timeout time.Duration) (*domainInfo, error) {
+	client, err := newHTTPClient(monitor, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	base := monitor.RDAPServer
+	if base == "" {
+		if base, err = rdapServerFor(client, domain); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(base, "/")+"/domain/"+domain, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "UptimeW33d/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var rdap struct {
+		Status []string `json:"status"`
+		Events []struct {
+			Action string `json:"eventAction"`
+			Date   string `json:"eventDate"`
+		} `json:"events"`
+		Entities []struct {
+			Roles      []string        `json:"roles"`
+			VCardArray json.RawMessage `json:"vcardArray"`
+		} `json:"entities"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, defaultMaxBodySize)).Decode(&rdap); err != nil {
+		return nil, fmt.Errorf("invalid RDAP response: %w", err)
+	}
+
+	info := &domainInfo{Status: rdap.Status, Source: "rdap"}
+	for _, e := range rdap.Events {
+		if e.Action == "expiration" {
+			if t, err := time.Parse(time.RFC3339, e.Date); err == nil {
+				info.Expiry = t
+			}
+		}
+	}
+	// Some registries leave the expiry out of RDAP, WHOIS may still have it
+	if info.Expiry.IsZero() {
+		return nil, errors.New("no expiration event in RDAP response")
+	}
+	for _, e := range rdap.Entities {
+		for _, role := range e.Roles {
+			if role == "registrar" {
+				info.Registrar = vcardName(e.VCardArray)
+			}
+		}
+	}
+	return info, nil
+}
+
+// vcardName returns the "fn" property of a jCard: ["vcard", [["fn", {}, "text", "Name"], ...]].
+func vcardName(raw json.RawMessage) string {
+	var card []interface{}
+	if json.Unmarshal(raw, &card) != nil || len(card) < 2 {
+		return ""
+	}
+	props, _ := card[1].([]interface{})
+	for _, p := range props {
+		prop, _ := p.([]interface{})
+		if len(prop) >= 4 && prop[0] == "fn" {
+			name, _ := prop[3].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// rdapServerFor finds the RDAP base URL for the domain's TLD (longest match).
+func rdapServerFor(client *http.Client, domain string) (string, error) {
+	rdapBootstrap.Lock()
+	services := rdapBootstrap.services
+	stale := services == nil || time.Since(rdapBootstrap.fetched) > rdapBootstrapTTL
+	rdapBootstrap.Unlock()
+
+	if stale {
+		fresh, err, _ := rdapBootstrap.fetch.Do("bootstrap", func() (interface{}, error) {
+			fetched, err := fetchRDAPBootstrap(client)
+			if err != nil {
+				return nil, err
+			}
+			rdapBootstrap.Lock()
+			rdapBootstrap.services = fetched
+			rdapBootstrap.fetched = time.Now()
+			rdapBootstrap.Unlock()
+			return fetched, nil
+		})
+		switch {
+		case err == nil:
+			services = fresh.(map[string]string)
+		case services == nil:
+			return "", err
+		}
+		// A failed refresh keeps using the previous map
+	}
+
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		if base, ok := services[strings.Join(labels[i:], ".")]; ok {
+			return base, nil
+		}
+	}
+	return "", errors.New("no RDAP service for this TLD")
+}
+
+// fetchRDAPBootstrap downloads the IANA DNS bootstrap file.
+func fetchRDAPBootstrap(client *http.Client) (map[string]string, error) {
+	resp, err := client.Get(rdapBootstrapURL)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var registry struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, defaultMaxBodySize)).Decode(&registry); err != nil {
+		return nil, fmt.Errorf("bootstrap: %w", err)
+	}
+	services := make(map[string]string)
+	for _, s := range registry.Services {
+		if len(s) < 2 || len(s[1]) == 0 {
+			continue
+		}
+		for _, tld := range s[0] {
+			services[strings.ToLower(tld)] = s[1][0]
+		}
+	}
+	return services, nil
+}
+
+// lookupWHOIS queries WHOISServer, or asks IANA which server handles the TLD.
+func lookupWHOIS(monitor models.Monitor, domain string, timeout time.Duration) (*domainInfo, error) {
+	server := monitor.WHOISServer
+	if server == "" {
+		tld := domain[strings.LastIndex(domain, ".")+1:]
+		resp, err := whoisQuery(ianaWHOIS, tld, timeout)
+		if err != nil {
+			return nil, err
+		}
+		server = whoisField(resp, "refer", "whois")
+		if server == "" {
+			return nil, errors.New("IANA has no WHOIS server for this TLD")
+		}
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "43")
+	}
+
+	resp, err := whoisQuery(server, domain, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &domainInfo{Source: "whois", Registrar: whoisField(resp, "registrar", "sponsoring registrar")}
+	if expiry := whoisField(resp, whoisExpiryKeys...); expiry != "" {
+		for _, layout := range whoisDateLayouts {
+			if t, err := time.Parse(layout, expiry); err == nil {
+				info.Expiry = t
+				break
+			}
+		}
+	}
+	for _, line := range strings.Split(resp, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "domain status") {
+			// "clientTransferProhibited https://icann.org/epp#clientTransferProhibited"
+			if fields := strings.Fields(value); len(fields) > 0 {
+				info.Status = append(info.Status, fields[0])
+			}
+		}
+	}
+	return info, nil
+}
+
+func whoisQuery(server, query string, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("tcp", server, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(conn, maxWHOISResponseSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// whoisField returns the value of the first "key: value" line matching one of
+// keys (case-insensitive).
+func whoisField(resp string, keys ...string) string {
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		for _, k := range keys {
+			if key == k {
+				if v := strings.TrimSpace(value); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package probe_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// newRDAPServer serves /domain/<name> with an expiry daysLeft days ahead.
+func newRDAPServer(daysLeft int, status string) *httptest.Server {
+	expiry := time.Now().AddDate(0, 0, daysLeft).UTC().Format(time.RFC3339)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprintf(w, `{
+			"objectClassName": "domain",
+			"ldhName": "EXAMPLE.COM",
+			"status": [%q],
+			"events": [
+				{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+				{"eventAction": "expiration", "eventDate": %q}
+			],
+			"entities": [{
+				"objectClassName": "entity",
+				"roles": ["registrar"],
+				"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "RESERVED-Internet Assigned Numbers Authority"]]]
+			}]
+		}`, status, expiry)
+	}))
+}
+
+// startWHOISServer answers every query with a thin-registry style record.
+func startWHOISServer(t *testing.T, daysLeft int) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	expiry := time.Now().AddDate(0, 0, daysLeft).UTC().Format("2006-01-02T15:04:05Z")
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			query, _ := bufio.NewReader(conn).ReadString('\n')
+			fmt.Fprintf(conn, "   Domain Name: %s\r\n"+
+				"   Registrar: Example Registrar, Inc.\r\n"+
+				"   Registry Expiry Date: %s\r\n"+
+				"   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited\r\n"+
+				">>> Last update of whois database: 2026-10-18T00:00:00Z <<<\r\n",
+				strings.ToUpper(strings.TrimSpace(query)), expiry)
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestDomainProbe_Check_RDAP(t *testing.T) {
+	p := probe.NewDomainProbe()
+
+	ok := newRDAPServer(200, "client transfer prohibited")
+	defer ok.Close()
+	m := models.Monitor{Type: models.TypeDomain, Target: "example.com", Timeout: 1, RDAPServer: ok.URL}
+	result := p.Check(m)
+	if !result.Success || result.Degraded {
+		t.Fatalf("Expected up, got %v/%v: %s", result.Success, result.Degraded, result.Message)
+	}
+	if result.Data["registrar"] != "RESERVED-Internet Assigned Numbers Authority" || result.Data["source"] != "rdap" {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+	if _, ok := result.Data["domain_expiry"].(time.Time); !ok {
+		t.Errorf("Expected domain_expiry time, got %v", result.Data["domain_expiry"])
+	}
+
+	m.ExpiryWarnDays = 365
+	if result := p.Check(m); !result.Success || !result.Degraded {
+		t.Errorf("Expected degraded inside the warning window, got %v/%v", result.Success, result.Degraded)
+	}
+	m.ExpiryCriticalDays = 300
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected down inside the critical window, got success")
+	}
+
+	// Lookups are cached and subdomains map to the registered domain
+	ok.Close()
+	m = models.Monitor{Type: models.TypeDomain, Target: "www.example.com", Timeout: 1, RDAPServer: ok.URL}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected the cached registration, got failure: %s", result.Message)
+	}
+
+	hold := newRDAPServer(200, "client hold")
+	defer hold.Close()
+	m = models.Monitor{Type: models.TypeDomain, Target: "example.com", Timeout: 1, RDAPServer: hold.URL}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected down for a domain on hold, got success")
+	}
+}
+
+func TestDomainProbe_Check_WHOISFallback(t *testing.T) {
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+	whois := startWHOISServer(t, 20)
+
+	p := probe.NewDomainProbe()
+	m := models.Monitor{
+		Type:        models.TypeDomain,
+		Target:      "example.com",
+		Timeout:     1,
+		RDAPServer:  broken.URL,
+		WHOISServer: whois,
+	}
+	result := p.Check(m)
+	if !result.Success || !result.Degraded {
+		t.Fatalf("Expected degraded (20 days left), got %v/%v: %s", result.Success, result.Degraded, result.Message)
+	}
+	if result.Data["source"] != "whois" || result.Data["registrar"] != "Example Registrar, Inc." {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+	if status, _ := result.Data["status"].([]string); len(status) != 1 || status[0] != "clientTransferProhibited" {
+		t.Errorf("Unexpected status: %v", result.Data["status"])
+	}
+
+	// An RDAP record without an expiration event falls back to WHOIS too
+	noExpiry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.Write([]byte(`{"objectClassName":"domain","status":["active"],"events":[{"eventAction":"registration","eventDate":"1995-08-14T04:00:00Z"}]}`))
+	}))
+	defer noExpiry.Close()
+	m.RDAPServer = noExpiry.URL
+	result = p.Check(m)
+	if !result.Success || result.Data["source"] != "whois" {
+		t.Errorf("Expected the WHOIS expiry, got %v from %v: %s", result.Success, result.Data["source"], result.Message)
+	}
+}
+
+func TestDomainProbe_Check_InvalidDomain(t *testing.T) {
+	p := probe.NewDomainProbe()
+	for _, target := range []string{"", "localhost", "co.uk"} {
+		m := models.Monitor{Type: models.TypeDomain, Target: target, Timeout: 1}
+		if result := p.Check(m); result.Success || !strings.HasPrefix(result.Message, "invalid domain") {
+			t.Errorf("%q: expected invalid domain, got %v: %s", target, result.Success, result.Message)
+		}
+	}
+}
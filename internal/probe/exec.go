@@ -0,0 +1,226 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+type ExecProbe struct {
+	BaseProbe
+	enabled    bool
+	allowed    []string
+	allowedEnv map[string]bool
+}
+
+// NewExecProbe returns the probe for exec monitors. Nothing runs unless
+// enabled, and only the commands (absolute paths) in allowed; monitors may
+// only set the allowedEnv keys.
+func NewExecProbe(enabled bool, allowed []string, allowedEnv []string) *ExecProbe {
+	p := &ExecProbe{enabled: enabled, allowed: allowed, allowedEnv: make(map[string]bool)}
+	for _, key := range allowedEnv {
+		p.allowedEnv[key] = true
+	}
+	return p
+}
+
+func (p *ExecProbe) Type() models.MonitorType {
+	return models.TypeExec
+}
+
+// Check runs monitor.Target with Arguments, Environment and WorkingDir and
+// reads the result the Nagios plugin way: exit 0 is up, 1 degraded, anything
+// else down; the first output line is the message and perfdata after "|"
+// ends up in Data["perfdata"]. The command only sees PATH plus Environment.
+func (p *ExecProbe) Check(monitor models.Monitor) Result {
+	if !p.enabled {
+		return p.RecordResult(false, "exec monitors are disabled in the server config", 0)
+	}
+	if !p.isAllowed(monitor.Target) {
+		return p.RecordResult(false, fmt.Sprintf("command not allowed: %s", monitor.Target), 0)
+	}
+
+	var args []string
+	if monitor.Arguments != "" {
+		if err := json.Unmarshal([]byte(monitor.Arguments), &args); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid arguments: %v", err), 0)
+		}
+	}
+	env := []string{"PATH=" + os.Getenv("PATH")}
+	if monitor.Environment != "" {
+		var vars map[string]string
+		if err := json.Unmarshal([]byte(monitor.Environment), &vars); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid environment: %v", err), 0)
+		}
+		for k, v := range vars {
+			if err := p.checkEnvKey(k); err != nil {
+				return p.RecordResult(false, err.Error(), 0)
+			}
+			env = append(env, k+"="+v)
+		}
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, monitor.Target, args...)
+	cmd.Env = env
+	cmd.Dir = monitor.WorkingDir
+	cmd.WaitDelay = time.Second // Don't hang on children keeping the pipes open
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return p.RecordResult(false, fmt.Sprintf("command timed out after %v", timeout), duration)
+	}
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return p.RecordResult(false, fmt.Sprintf("failed to run command: %v", err), duration)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	output := stdout.String()
+	if strings.TrimSpace(output) == "" {
+		output = stderr.String()
+	}
+	text, perfdata := parsePluginOutput(output)
+	if text == "" {
+		text = fmt.Sprintf("Exit %d", exitCode)
+	}
+
+	res := p.RecordResult(exitCode == 0 || exitCode == 1, truncate(text, 200), duration)
+	res.Degraded = exitCode == 1
+	res.Data["exit_code"] = exitCode
+	res.Data["output"] = truncate(strings.TrimSpace(output), 4096)
+	if len(perfdata) > 0 {
+		res.Data["perfdata"] = perfdata
+	}
+	return res
+}
+
+// isAllowed matches command against the allow list, an empty list allows
+// nothing. Only absolute paths count, a relative one would resolve against
+// PATH or WorkingDir.
+func (p *ExecProbe) isAllowed(command string) bool {
+	if !filepath.IsAbs(command) {
+		return false
+	}
+	for _, a := range p.allowed {
+		if filepath.IsAbs(a) && filepath.Clean(a) == filepath.Clean(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEnvKey rejects environment variables that would let a monitor run
+// other code under an allowed command (PATH, dynamic loader settings). Only
+// the configured keys may be set at all.
+func (p *ExecProbe) checkEnvKey(key string) error {
+	upper := strings.ToUpper(key)
+	if upper == "PATH" || strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") {
+		return fmt.Errorf("environment variable not allowed: %s", key)
+	}
+	if !p.allowedEnv[key] {
+		return fmt.Errorf("environment variable not allowed: %s", key)
+	}
+	return nil
+}
+
+// parsePluginOutput splits Nagios plugin output into the status text (first
+// line, before "|") and the perfdata found on the first line and after the
+// "|" of the long output.
+func parsePluginOutput(output string) (string, map[string]map[string]interface{}) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	text, perf, _ := strings.Cut(lines[0], "|")
+
+	var rawPerf []string
+	rawPerf = append(rawPerf, perf)
+	inPerf := false
+	for _, line := range lines[1:] {
+		if !inPerf {
+			if _, after, ok := strings.Cut(line, "|"); ok {
+				inPerf = true
+				rawPerf = append(rawPerf, after)
+			}
+			continue
+		}
+		rawPerf = append(rawPerf, line)
+	}
+
+	perfdata := make(map[string]map[string]interface{})
+	for _, chunk := range rawPerf {
+		for _, item := range splitPerfdata(chunk) {
+			label, values, ok := strings.Cut(item, "=")
+			if !ok {
+				continue
+			}
+			label = strings.ReplaceAll(strings.Trim(label, "'"), "''", "'")
+			fields := strings.Split(values, ";")
+
+			// Value is a number followed by an optional unit of measurement
+			num := strings.TrimRightFunc(fields[0], func(r rune) bool {
+				return !(r >= '0' && r <= '9' || r == '.')
+			})
+			value, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				continue
+			}
+			entry := map[string]interface{}{"value": value}
+			if uom := fields[0][len(num):]; uom != "" {
+				entry["uom"] = uom
+			}
+			for i, key := range []string{"warn", "crit", "min", "max"} {
+				if i+1 < len(fields) && fields[i+1] != "" {
+					entry[key] = fields[i+1]
+				}
+			}
+			perfdata[label] = entry
+		}
+	}
+	return strings.TrimSpace(text), perfdata
+}
+
+// splitPerfdata splits on spaces outside single-quoted labels.
+func splitPerfdata(s string) []string {
+	var items []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if cur.Len() > 0 {
+				items = append(items, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		items = append(items, cur.String())
+	}
+	return items
+}
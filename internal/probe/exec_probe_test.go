@@ -0,0 +1,113 @@
+package probe_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// writePlugin drops a shell script into a temp dir and returns its path.
+func writePlugin(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "check_test.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	return path
+}
+
+func TestExecProbe_Check_NagiosPlugin(t *testing.T) {
+	script := writePlugin(t, `
+echo "DISK $1 - free space: / 3326 MB (56%) | '/ free'=3326MB;1000;500;0;5936 inodes=56%"
+echo "long output line"
+echo "workdir=$(pwd) region=$REGION"
+exit ${EXIT:-0}
+`)
+	dir := t.TempDir()
+
+	p := probe.NewExecProbe(true, []string{script}, []string{"REGION", "EXIT"})
+	m := models.Monitor{
+		Type:        models.TypeExec,
+		Target:      script,
+		Arguments:   `["OK"]`,
+		Environment: `{"REGION":"eu-west"}`,
+		WorkingDir:  dir,
+		Timeout:     5,
+	}
+	result := p.Check(m)
+	if !result.Success || result.Degraded {
+		t.Fatalf("Expected up, got %v/%v: %s", result.Success, result.Degraded, result.Message)
+	}
+	if result.Message != "DISK OK - free space: / 3326 MB (56%)" {
+		t.Errorf("Unexpected message: %q", result.Message)
+	}
+	perf, ok := result.Data["perfdata"].(map[string]map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected perfdata, got %v", result.Data["perfdata"])
+	}
+	if free := perf["/ free"]; free["value"] != 3326.0 || free["uom"] != "MB" || free["crit"] != "500" || free["max"] != "5936" {
+		t.Errorf("Unexpected perfdata for '/ free': %v", free)
+	}
+	if inodes := perf["inodes"]; inodes["value"] != 56.0 || inodes["uom"] != "%" {
+		t.Errorf("Unexpected perfdata for inodes: %v", inodes)
+	}
+	if out, _ := result.Data["output"].(string); !strings.Contains(out, "workdir="+dir) || !strings.Contains(out, "region=eu-west") {
+		t.Errorf("Expected working dir and environment in output, got %q", out)
+	}
+
+	m.Environment = `{"EXIT":"1"}`
+	if result := p.Check(m); !result.Success || !result.Degraded {
+		t.Errorf("Expected degraded for exit 1, got %v/%v", result.Success, result.Degraded)
+	}
+	m.Environment = `{"EXIT":"2"}`
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected down for exit 2, got success")
+	}
+}
+
+func TestExecProbe_Check_Guards(t *testing.T) {
+	script := writePlugin(t, "sleep 5\n")
+
+	m := models.Monitor{Type: models.TypeExec, Target: script, Timeout: 1}
+	if result := probe.NewExecProbe(false, nil, nil).Check(m); result.Success {
+		t.Errorf("Expected failure when exec is disabled, got success")
+	}
+	if result := probe.NewExecProbe(true, nil, nil).Check(m); result.Success || !strings.Contains(result.Message, "not allowed") {
+		t.Errorf("Expected an empty allow list to allow nothing, got %v: %s", result.Success, result.Message)
+	}
+	if result := probe.NewExecProbe(true, []string{"/usr/lib/nagios/plugins/check_disk"}, nil).Check(m); result.Success {
+		t.Errorf("Expected failure for a command outside the allow list, got success")
+	}
+	if result := probe.NewExecProbe(true, []string{script}, nil).Check(m); result.Success {
+		t.Errorf("Expected timeout failure, got success")
+	}
+
+	// A relative command would resolve against PATH or WorkingDir
+	rel := m
+	rel.Target = filepath.Base(script)
+	rel.WorkingDir = filepath.Dir(script)
+	if result := probe.NewExecProbe(true, []string{filepath.Base(script)}, nil).Check(rel); result.Success || !strings.Contains(result.Message, "not allowed") {
+		t.Errorf("Expected relative command to be rejected, got %v: %s", result.Success, result.Message)
+	}
+
+	// Loader and PATH variables would run other code under an allowed command
+	m.Environment = `{"LD_PRELOAD":"/tmp/evil.so"}`
+	if result := probe.NewExecProbe(true, []string{script}, []string{"LD_PRELOAD"}).Check(m); result.Success || !strings.Contains(result.Message, "LD_PRELOAD") {
+		t.Errorf("Expected LD_PRELOAD to be rejected, got %v: %s", result.Success, result.Message)
+	}
+	m.Environment = `{"REGION":"eu-west"}`
+	if result := probe.NewExecProbe(true, []string{script}, nil).Check(m); !strings.Contains(result.Message, "REGION") {
+		t.Errorf("Expected unlisted variable to be rejected, got %s", result.Message)
+	}
+	if result := probe.NewExecProbe(true, []string{script}, []string{"REGION"}).Check(m); strings.Contains(result.Message, "not allowed") {
+		t.Errorf("Expected listed variable to be accepted, got %s", result.Message)
+	}
+}
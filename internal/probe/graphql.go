@@ -0,0 +1,169 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"uptime_w33d/internal/models"
+)
+
+type GraphQLProbe struct {
+	BaseProbe
+}
+
+func NewGraphQLProbe() *GraphQLProbe {
+	return &GraphQLProbe{}
+}
+
+func (p *GraphQLProbe) Type() models.MonitorType {
+	return models.TypeGraphQL
+}
+
+// Check POSTs monitor.Query with Variables to the endpoint. Any entry in the
+// "errors" array fails the check whatever the HTTP status; JSONPath/JSONValue
+// and json/body assertions are evaluated against "data".
+func (p *GraphQLProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	assertions, err := ParseAssertions(monitor.Assertions)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+	if strings.TrimSpace(monitor.Query) == "" {
+		return p.RecordResult(false, "GraphQL query is required", 0)
+	}
+
+	payload := map[string]interface{}{"query": monitor.Query}
+	if monitor.Variables != "" {
+		var vars map[string]interface{}
+		if err := json.Unmarshal([]byte(monitor.Variables), &vars); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid variables: %v", err), 0)
+		}
+		payload["variables"] = vars
+	}
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+
+	client, err := newHTTPClient(monitor, timeout)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+	req, err := http.NewRequest(http.MethodPost, monitor.Target, bytes.NewReader(reqBody))
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid URL: %v", err), 0)
+	}
+	req.Header.Set("User-Agent", "UptimeW33d/1.0")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	if monitor.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(monitor.Headers), &headers); err == nil {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+		}
+	}
+
+	timer := &httpTimer{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("request failed: %v", err), duration)
+	}
+	defer resp.Body.Close()
+
+	body, truncated, err := readBody(resp.Body, monitor.MaxBodySize)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("failed to read body: %v", err), duration)
+	}
+	timer.mark(&timer.bodyDone)
+
+	record := func(res Result) Result {
+		res.Data["status_code"] = resp.StatusCode
+		res.Data["body_size"] = len(body)
+		if truncated {
+			res.Data["body_truncated"] = true
+		}
+		timer.record(res.Data)
+		if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+			res.Data["cert_expiry"] = resp.TLS.PeerCertificates[0].NotAfter
+		}
+		return res
+	}
+
+	if !gjson.ValidBytes(body) {
+		return record(p.RecordResult(false, fmt.Sprintf("response is not JSON (HTTP %d)", resp.StatusCode), duration))
+	}
+
+	// GraphQL errors, reported with any status code
+	if errs := gjson.GetBytes(body, "errors"); errs.IsArray() && len(errs.Array()) > 0 {
+		var messages []string
+		for _, e := range errs.Array() {
+			messages = append(messages, truncate(e.Get("message").String(), 200))
+		}
+		msg := fmt.Sprintf("GraphQL error: %s", messages[0])
+		if len(messages) > 1 {
+			msg += fmt.Sprintf(" (+%d more)", len(messages)-1)
+		}
+		res := record(p.RecordResult(false, msg, duration))
+		res.Data["errors"] = messages
+		return res
+	}
+
+	expected := monitor.ExpectedStatus
+	if expected == "" {
+		expected = "2xx"
+	}
+	ok, err := StatusMatches(resp.StatusCode, expected)
+	if err != nil {
+		return record(p.RecordResult(false, fmt.Sprintf("invalid expected status: %v", err), duration))
+	}
+	if !ok {
+		return record(p.RecordResult(false, fmt.Sprintf("Unexpected status: %d (expected %s)", resp.StatusCode, expected), duration))
+	}
+
+	data := gjson.GetBytes(body, "data")
+	if !data.Exists() || data.Type == gjson.Null {
+		return record(p.RecordResult(false, "response has no data", duration))
+	}
+
+	success := true
+	msg := fmt.Sprintf("HTTP %d, no GraphQL errors", resp.StatusCode)
+	if monitor.JSONPath != "" {
+		res := data.Get(monitor.JSONPath)
+		if !res.Exists() {
+			success = false
+			msg = fmt.Sprintf("JSON Path 'data.%s' not found", monitor.JSONPath)
+		} else if monitor.JSONValue != "" && res.String() != monitor.JSONValue {
+			success = false
+			msg = fmt.Sprintf("JSON Value mismatch: expected '%s', got '%s'", monitor.JSONValue, res.String())
+		}
+	}
+
+	var report []AssertionResult
+	if len(assertions) > 0 {
+		var failed *AssertionResult
+		report, failed = EvaluateAssertions(assertions, resp, []byte(data.Raw), truncated)
+		if success && failed != nil {
+			success = false
+			msg = fmt.Sprintf("Assertion failed: %s", failed.Message)
+		}
+	}
+
+	res := record(p.RecordResult(success, msg, duration))
+	if report != nil {
+		res.Data["assertions"] = report
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package probe_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// newGraphQLServer mimics a gateway that answers 200 even for failed queries.
+func newGraphQLServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "brokenField"):
+			w.Write([]byte(`{"data":null,"errors":[{"message":"Cannot query field \"brokenField\""},{"message":"second"}]}`))
+		case req.Variables["id"] == "42":
+			w.Write([]byte(`{"data":{"user":{"id":"42","name":"Ada","roles":["admin","ops"]}}}`))
+		default:
+			w.Write([]byte(`{"data":{"user":null}}`))
+		}
+	}))
+}
+
+func TestGraphQLProbe_Check(t *testing.T) {
+	ts := newGraphQLServer()
+	defer ts.Close()
+
+	p := probe.NewGraphQLProbe()
+	m := models.Monitor{
+		Type:       models.TypeGraphQL,
+		Target:     ts.URL,
+		Timeout:    1,
+		Query:      `query($id: ID!) { user(id: $id) { id name roles } }`,
+		Variables:  `{"id":"42"}`,
+		JSONPath:   "user.name",
+		JSONValue:  "Ada",
+		Assertions: `[{"source":"json","property":"user.roles.#","operator":">=","target":"2"}]`,
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+
+	m.Variables = `{"id":"7"}`
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure when the JSON path is missing, got success")
+	}
+
+	m.Query = `{ brokenField }`
+	m.Variables = ""
+	result = p.Check(m)
+	if result.Success {
+		t.Fatalf("Expected failure for GraphQL errors on HTTP 200, got success")
+	}
+	if !strings.Contains(result.Message, "brokenField") || !strings.Contains(result.Message, "+1 more") {
+		t.Errorf("Unexpected message: %s", result.Message)
+	}
+	if result.Data["status_code"] != 200 {
+		t.Errorf("Expected status 200 to be recorded, got %v", result.Data["status_code"])
+	}
+}
@@ -0,0 +1,88 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"uptime_w33d/internal/models"
+)
+
+type GRPCProbe struct {
+	BaseProbe
+}
+
+func NewGRPCProbe() *GRPCProbe {
+	return &GRPCProbe{}
+}
+
+func (p *GRPCProbe) Type() models.MonitorType {
+	return models.TypeGRPC
+}
+
+// Check calls grpc.health.v1.Health/Check on monitor.Target (host:port).
+// GRPCService selects the service (empty = overall server health), Headers
+// are sent as metadata and ExpectedStatus defaults to SERVING.
+func (p *GRPCProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	creds := insecure.NewCredentials()
+	if monitor.UseTLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: !monitor.VerifyTLS})
+	}
+
+	conn, err := grpc.NewClient(monitor.Target, grpc.WithTransportCredentials(creds), grpc.WithUserAgent("UptimeW33d/1.0"))
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid target: %v", err), 0)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Metadata from custom headers
+	if monitor.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(monitor.Headers), &headers); err == nil {
+			for k, v := range headers {
+				ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(k), v)
+			}
+		}
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: monitor.GRPCService})
+	duration := time.Since(start)
+
+	if err != nil {
+		st := status.Convert(err)
+		res := p.RecordResult(false, fmt.Sprintf("health check failed: %s: %s", st.Code(), st.Message()), duration)
+		res.Data["grpc_code"] = st.Code().String()
+		return res
+	}
+
+	expected := strings.ToUpper(monitor.ExpectedStatus)
+	if expected == "" {
+		expected = healthpb.HealthCheckResponse_SERVING.String()
+	}
+
+	got := resp.GetStatus().String()
+	success := got == expected
+	msg := fmt.Sprintf("Status %s", got)
+	if !success {
+		msg = fmt.Sprintf("Unexpected status: %s (expected %s)", got, expected)
+	}
+
+	res := p.RecordResult(success, msg, duration)
+	res.Data["serving_status"] = got
+	return res
+}
@@ -0,0 +1,79 @@
+package probe_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	// Reject calls without the expected API key metadata
+	auth := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if vals := md.Get("x-api-key"); len(vals) == 0 || vals[0] != "letmein" {
+			return nil, status.Error(codes.Unauthenticated, "missing api key")
+		}
+		return handler(ctx, req)
+	}
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(auth))
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	return ln.Addr().String(), hs
+}
+
+func TestGRPCProbe_Check(t *testing.T) {
+	addr, hs := startHealthServer(t)
+	hs.SetServingStatus("orders.v1.Orders", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("billing.v1.Billing", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	p := probe.NewGRPCProbe()
+	m := models.Monitor{
+		Type:        models.TypeGRPC,
+		Target:      addr,
+		Timeout:     2,
+		Headers:     `{"X-Api-Key":"letmein"}`,
+		GRPCService: "orders.v1.Orders",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success, got failure: %s", result.Message)
+	}
+
+	m.GRPCService = "billing.v1.Billing"
+	result := p.Check(m)
+	if result.Success {
+		t.Errorf("Expected failure for NOT_SERVING service, got success")
+	}
+	if result.Data["serving_status"] != "NOT_SERVING" {
+		t.Errorf("Expected NOT_SERVING in data, got %v", result.Data["serving_status"])
+	}
+
+	m.ExpectedStatus = "NOT_SERVING"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success when NOT_SERVING is expected, got failure: %s", result.Message)
+	}
+
+	m.Headers = ""
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure without metadata, got success")
+	}
+}
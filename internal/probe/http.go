@@ -1,14 +1,23 @@
 package probe
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/tidwall/gjson"
 	"uptime_w33d/internal/models"
 )
@@ -29,14 +38,15 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 	start := time.Now()
 	timeout := time.Duration(monitor.Timeout) * time.Second
 	
-	// Prepare Client with Timeout and SSL config
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // Option to verify SSL?
-		DisableKeepAlives: true,
+	assertions, err := ParseAssertions(monitor.Assertions)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
 	}
-	client := &http.Client{
-		Timeout:   timeout,
-		Transport: tr,
+
+	// Prepare Client with Timeout, SSL, redirect and proxy config
+	client, err := newHTTPClient(monitor, timeout)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
 	}
 
 	method := monitor.Method
@@ -59,6 +69,10 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 	// Add User-Agent
 	req.Header.Set("User-Agent", "UptimeW33d/1.0")
 
+	// Trace connection phases for the timing breakdown
+	timer := &httpTimer{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+
 	// Add Custom Headers
 	if monitor.Headers != "" {
 		var headers map[string]string
@@ -77,30 +91,24 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 	}
 	defer resp.Body.Close()
 	
-	// Read Body for Advanced Checks (Keyword/JSON)
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Read Body for Advanced Checks (Keyword/JSON), capped to protect memory
+	bodyBytes, truncated, err := readBody(resp.Body, monitor.MaxBodySize)
 	if err != nil {
 		return p.RecordResult(false, fmt.Sprintf("failed to read body: %v", err), duration)
 	}
+	timer.mark(&timer.bodyDone)
 	bodyStr := string(bodyBytes)
 
 	// Check Status Code
-	success := false
 	expected := monitor.ExpectedStatus
 	if expected == "" {
 		expected = "200" // Default
 	}
 
-	// Simple status check logic
-	if expected == "2xx" {
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			success = true
-		}
-	} else {
-		// Exact match
-		if fmt.Sprintf("%d", resp.StatusCode) == expected {
-			success = true
-		}
+	// List/range/class status check, e.g. "200-299,301,404"
+	success, err := StatusMatches(resp.StatusCode, expected)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid expected status: %v", err), duration)
 	}
 
 	msg := fmt.Sprintf("HTTP %d %s", resp.StatusCode, resp.Status)
@@ -108,13 +116,29 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 		msg = fmt.Sprintf("Unexpected status: %d (expected %s)", resp.StatusCode, expected)
 	}
 
+	// Redirect target check (only meaningful when redirects are not followed)
+	if success && monitor.ExpectedLocation != "" {
+		location := resp.Header.Get("Location")
+		if !locationMatches(resp, location, monitor.ExpectedLocation) {
+			success = false
+			msg = fmt.Sprintf("Location mismatch: expected '%s', got '%s'", monitor.ExpectedLocation, location)
+		}
+	}
+
 	// --- Advanced Checks ---
 	if success {
 		// Keyword Check
 		if monitor.Type == models.TypeHTTPKeyword && monitor.Keyword != "" {
-			if !strings.Contains(bodyStr, monitor.Keyword) {
+			found := strings.Contains(bodyStr, monitor.Keyword)
+			if monitor.InvertKeyword && found {
+				success = false
+				msg = fmt.Sprintf("Keyword '%s' found", monitor.Keyword)
+			} else if !monitor.InvertKeyword && !found {
 				success = false
 				msg = fmt.Sprintf("Keyword '%s' not found", monitor.Keyword)
+				if truncated {
+					msg += fmt.Sprintf(" (body truncated at %d bytes)", len(bodyBytes))
+				}
 			}
 		}
 
@@ -125,6 +149,9 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 			if !res.Exists() {
 				success = false
 				msg = fmt.Sprintf("JSON Path '%s' not found", monitor.JSONPath)
+				if truncated {
+					msg += fmt.Sprintf(" (body truncated at %d bytes)", len(bodyBytes))
+				}
 			} else {
 				// Compare Value (String comparison for now)
 				if monitor.JSONValue != "" && res.String() != monitor.JSONValue {
@@ -135,8 +162,50 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 		}
 	}
 
+	// Assertion List (all are evaluated so the report is complete)
+	var report []AssertionResult
+	if len(assertions) > 0 {
+		var failed *AssertionResult
+		report, failed = EvaluateAssertions(assertions, resp, bodyBytes, truncated)
+		if success && failed != nil {
+			success = false
+			msg = fmt.Sprintf("Assertion failed: %s", failed.Message)
+		}
+	}
+
+	// Content Change Detection (only hash healthy responses, so error pages don't become the baseline)
+	var contentHash string
+	if success && monitor.DetectChanges {
+		contentHash, err = hashContent(bodyBytes, monitor.HashSelector)
+		if err != nil {
+			success = false
+			msg = err.Error()
+		} else if monitor.ContentHash != "" && contentHash != monitor.ContentHash {
+			success = false
+			msg = "Content changed since last acknowledged"
+		}
+	}
+
 	res := p.RecordResult(success, msg, duration)
 	res.Data["status_code"] = resp.StatusCode
+	res.Data["body_size"] = len(bodyBytes)
+	if truncated {
+		res.Data["body_truncated"] = true
+	}
+	if contentHash != "" {
+		res.Data["content_hash"] = contentHash
+		if monitor.ContentHash != "" && contentHash != monitor.ContentHash {
+			res.Data["previous_hash"] = monitor.ContentHash
+		}
+	}
+	if report != nil {
+		res.Data["assertions"] = report
+	}
+	res.Data["http_version"] = resp.Proto
+	if location := resp.Header.Get("Location"); location != "" {
+		res.Data["location"] = location
+	}
+	timer.record(res.Data)
 
 	// SSL Check
 	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
@@ -146,3 +215,196 @@ func (p *HTTPProbe) Check(monitor models.Monitor) Result {
 
 	return res
 }
+
+// defaultMaxBodySize is the body read limit when the monitor doesn't set one.
+const defaultMaxBodySize = 1 << 20 // 1 MiB
+
+// readBody reads at most limit bytes (defaultMaxBodySize if limit <= 0).
+// The rest of the body is discarded and truncated is reported as true.
+func readBody(body io.Reader, limit int) (data []byte, truncated bool, err error) {
+	if limit <= 0 {
+		limit = defaultMaxBodySize
+	}
+	data, err = io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// hashContent returns the SHA-256 of the body, or of the text of the
+// elements matching selector (a CSS selector) when one is given.
+// Hashing text rather than markup ignores per-request attributes like CSRF tokens.
+func hashContent(body []byte, selector string) (string, error) {
+	content := body
+	if selector != "" {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse HTML: %v", err)
+		}
+		sel := doc.Find(selector)
+		if sel.Length() == 0 {
+			return "", fmt.Errorf("hash selector '%s' matched nothing", selector)
+		}
+		content = []byte(strings.TrimSpace(sel.Text()))
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// defaultMaxRedirects mirrors the net/http client default.
+const defaultMaxRedirects = 10
+
+// newHTTPClient builds a client honouring the monitor's redirect policy,
+// proxy, HTTP version and IP family settings.
+func newHTTPClient(monitor models.Monitor, timeout time.Duration) (*http.Client, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	network := "tcp"
+	switch monitor.IPVersion {
+	case "", "any":
+	case "4":
+		network = "tcp4"
+	case "6":
+		network = "tcp6"
+	default:
+		return nil, fmt.Errorf("invalid ip version: %s", monitor.IPVersion)
+	}
+
+	tr := &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, // Option to verify SSL?
+		DisableKeepAlives: true,
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+
+	// Proxy: http://, https:// or socks5:// URL
+	if monitor.Proxy != "" {
+		proxyURL, err := url.Parse(monitor.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", monitor.Proxy)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme: %s", proxyURL.Scheme)
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// HTTP version: HTTP/1.1 unless "auto" negotiates HTTP/2 over TLS or "2"
+	// forces it (h2c for plain http)
+	protocols := new(http.Protocols)
+	switch monitor.HTTPVersion {
+	case "", "1.1":
+		protocols.SetHTTP1(true)
+	case "auto":
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	case "2":
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		return nil, fmt.Errorf("invalid http version: %s", monitor.HTTPVersion)
+	}
+	tr.Protocols = protocols
+
+	maxRedirects := defaultMaxRedirects
+	if monitor.MaxRedirects != nil {
+		maxRedirects = *monitor.MaxRedirects
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: tr,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if maxRedirects <= 0 {
+				// Don't follow, return the 3xx response itself
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// locationMatches compares the Location header with the expected value,
+// accepting either the raw header or the URL it resolves to.
+func locationMatches(resp *http.Response, location, expected string) bool {
+	if location == expected {
+		return true
+	}
+	if loc, err := resp.Location(); err == nil {
+		return loc.String() == expected
+	}
+	return false
+}
+
+// httpTimer collects the phase timestamps reported by httptrace. The
+// callbacks can run on the transport's dial goroutines, hence the mutex.
+type httpTimer struct {
+	mu                        sync.Mutex
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest              time.Time
+	firstByte                 time.Time
+	bodyDone                  time.Time
+}
+
+func (t *httpTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		// Dual-stack dialing can race several connects, keep the first attempt
+		// and the first one that succeeded
+		ConnectStart: func(string, string) { t.markOnce(&t.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.markOnce(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+// mark sets the timestamp to now.
+func (t *httpTimer) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+// markOnce sets the timestamp to now unless it is already set.
+func (t *httpTimer) markOnce(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// record stores each phase duration in data (as time.Duration).
+// Phases that did not happen, e.g. DNS for an IP target or TLS for plain HTTP, are left out.
+func (t *httpTimer) record(data map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	phase := func(key string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			data[key] = to.Sub(from)
+		}
+	}
+	phase("timing_dns", t.dnsStart, t.dnsDone)
+	phase("timing_connect", t.connectStart, t.connectDone)
+	phase("timing_tls", t.tlsStart, t.tlsDone)
+	phase("timing_ttfb", t.wroteRequest, t.firstByte)
+	phase("timing_transfer", t.firstByte, t.bodyDone)
+}
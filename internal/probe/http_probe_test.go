@@ -3,6 +3,7 @@ package probe_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -77,3 +78,395 @@ func TestHTTPProbe_Check_Timeout(t *testing.T) {
 		t.Errorf("Expected failure due to connection refused, got success")
 	}
 }
+
+func TestHTTPProbe_Check_TimingBreakdown(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTP,
+		Target:  ts.URL,
+		Timeout: 1,
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+
+	for _, key := range []string{"timing_connect", "timing_ttfb", "timing_transfer"} {
+		if _, ok := result.Data[key].(time.Duration); !ok {
+			t.Errorf("Expected %s in result data", key)
+		}
+	}
+	if ttfb := result.Data["timing_ttfb"].(time.Duration); ttfb < 20*time.Millisecond {
+		t.Errorf("Expected TTFB >= 20ms, got %v", ttfb)
+	}
+	if _, ok := result.Data["timing_tls"]; ok {
+		t.Errorf("Did not expect TLS timing for plain HTTP")
+	}
+}
+
+func TestHTTPProbe_Check_RedirectNotFollowed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	noFollow := 0
+	m := models.Monitor{
+		Type:             models.TypeHTTP,
+		Target:           ts.URL + "/old",
+		Timeout:          1,
+		ExpectedStatus:   "301",
+		MaxRedirects:     &noFollow,
+		ExpectedLocation: "/new",
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Errorf("Expected success, got failure: %s", result.Message)
+	}
+
+	m.ExpectedLocation = ts.URL + "/elsewhere"
+	result = p.Check(m)
+	if result.Success {
+		t.Errorf("Expected failure due to Location mismatch, got success")
+	}
+}
+
+func TestHTTPProbe_Check_MaxRedirectsExceeded(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	limit := 2
+	m := models.Monitor{
+		Type:         models.TypeHTTP,
+		Target:       ts.URL,
+		Timeout:      1,
+		MaxRedirects: &limit,
+	}
+
+	result := p.Check(m)
+	if result.Success {
+		t.Errorf("Expected failure after too many redirects, got success")
+	}
+}
+
+func TestHTTPProbe_Check_HTTPVersion(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	for version, proto := range map[string]string{"": "HTTP/1.1", "1.1": "HTTP/1.1", "auto": "HTTP/2.0", "2": "HTTP/2.0"} {
+		m := models.Monitor{
+			Type:        models.TypeHTTP,
+			Target:      ts.URL,
+			Timeout:     1,
+			HTTPVersion: version,
+		}
+		result := p.Check(m)
+		if !result.Success {
+			t.Fatalf("Expected success for HTTP %s, got failure: %s", version, result.Message)
+		}
+		if result.Data["http_version"] != proto {
+			t.Errorf("Expected %s, got %v", proto, result.Data["http_version"])
+		}
+	}
+}
+
+func TestHTTPProbe_Check_Proxy(t *testing.T) {
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute target URL
+		proxied = r.URL.Host == "upstream.invalid"
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTP,
+		Target:  "http://upstream.invalid/health",
+		Timeout: 1,
+		Proxy:   proxy.URL,
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Errorf("Expected success, got failure: %s", result.Message)
+	}
+	if !proxied {
+		t.Errorf("Expected request to go through the proxy")
+	}
+}
+
+func TestHTTPProbe_Check_IPVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:      models.TypeHTTP,
+		Target:    ts.URL, // 127.0.0.1
+		Timeout:   1,
+		IPVersion: "4",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success over IPv4, got failure: %s", result.Message)
+	}
+
+	m.IPVersion = "6"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure dialing an IPv4 address over IPv6, got success")
+	}
+}
+
+func TestStatusMatches(t *testing.T) {
+	cases := []struct {
+		code int
+		spec string
+		want bool
+	}{
+		{200, "", true},
+		{201, "2xx", true},
+		{301, "200-299,301,404", true},
+		{404, "200-299,301,404", true},
+		{302, "200-299,301,404", false},
+		{503, "5XX", true},
+	}
+	for _, c := range cases {
+		got, err := probe.StatusMatches(c.code, c.spec)
+		if err != nil {
+			t.Fatalf("StatusMatches(%d, %q) error: %v", c.code, c.spec, err)
+		}
+		if got != c.want {
+			t.Errorf("StatusMatches(%d, %q) = %v, want %v", c.code, c.spec, got, c.want)
+		}
+	}
+
+	if _, err := probe.StatusMatches(200, "300-200"); err == nil {
+		t.Errorf("Expected error for inverted range")
+	}
+}
+
+func TestHTTPProbe_Check_Assertions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Version", "v2.3.1")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok","queue":{"depth":12},"regions":["eu","us"]}`))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTP,
+		Target:  ts.URL,
+		Timeout: 1,
+		Assertions: `[
+			{"source":"header","property":"content-type","operator":"contains","target":"json"},
+			{"source":"header","property":"X-Version","operator":"regex","target":"^v2\\."},
+			{"source":"body","operator":"not_contains","target":"error"},
+			{"source":"size","operator":"<","target":"1024"},
+			{"source":"json","property":"status","operator":"==","target":"ok"},
+			{"source":"json","property":"queue.depth","operator":"<","target":"100"},
+			{"source":"json","property":"regions","operator":"contains","target":"us"},
+			{"source":"json","property":"queue","operator":"exists"}
+		]`,
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	report, ok := result.Data["assertions"].([]probe.AssertionResult)
+	if !ok || len(report) != 8 {
+		t.Fatalf("Expected assertion report with 8 entries, got %v", result.Data["assertions"])
+	}
+
+	m.Assertions = `[
+		{"source":"json","property":"status","operator":"==","target":"ok"},
+		{"source":"json","property":"queue.depth","operator":">","target":"100"}
+	]`
+	result = p.Check(m)
+	if result.Success {
+		t.Fatalf("Expected failure due to queue depth assertion, got success")
+	}
+	report = result.Data["assertions"].([]probe.AssertionResult)
+	if !report[0].Passed || report[1].Passed {
+		t.Errorf("Expected only the second assertion to fail, got %+v", report)
+	}
+	if report[1].Actual != "12" {
+		t.Errorf("Expected actual value 12 in report, got %q", report[1].Actual)
+	}
+}
+
+func TestHTTPProbe_Check_InvertKeyword(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Internal error: database unavailable"))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:          models.TypeHTTPKeyword,
+		Target:        ts.URL,
+		Timeout:       1,
+		Keyword:       "error",
+		InvertKeyword: true,
+	}
+
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure because keyword is present, got success")
+	}
+}
+
+func TestHTTPProbe_Check_MarkupAssertions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/soap" {
+			w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+			w.Write([]byte(`<?xml version="1.0"?>
+<Envelope><Body><StatusResponse code="0"><Status>OK</Status></StatusResponse></Body></Envelope>`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Shop</title></head><body>
+<div id="cart" data-items="3"><span class="total"> 42.00 </span></div>
+<a class="login" href="/login">Sign in</a>
+</body></html>`))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTP,
+		Target:  ts.URL,
+		Timeout: 1,
+		Assertions: `[
+			{"source":"css","property":"#cart .total","operator":"==","target":"42.00"},
+			{"source":"css","property":"#cart","attribute":"data-items","operator":">=","target":"1"},
+			{"source":"css","property":"a.login","attribute":"href","operator":"regex","target":"^/login"},
+			{"source":"xpath","property":"//title","operator":"==","target":"Shop"},
+			{"source":"css","property":".error-banner","operator":"not_exists"}
+		]`,
+	}
+	if result := p.Check(m); !result.Success {
+		t.Fatalf("Expected HTML assertions to pass, got failure: %s", result.Message)
+	}
+
+	m.Target = ts.URL + "/soap"
+	m.Assertions = `[
+		{"source":"xpath","property":"//StatusResponse/Status","operator":"==","target":"OK"},
+		{"source":"xpath","property":"//StatusResponse","attribute":"code","operator":"==","target":"0"}
+	]`
+	if result := p.Check(m); !result.Success {
+		t.Fatalf("Expected XML assertions to pass, got failure: %s", result.Message)
+	}
+
+	m.Assertions = `[{"source":"xpath","property":"//Fault","operator":"exists"}]`
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for missing XML node, got success")
+	}
+}
+
+func TestHTTPProbe_Check_MaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 64*1024))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:        models.TypeHTTP,
+		Target:      ts.URL,
+		Timeout:     1,
+		MaxBodySize: 1024,
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["body_size"] != 1024 {
+		t.Errorf("Expected body capped at 1024 bytes, got %v", result.Data["body_size"])
+	}
+	if result.Data["body_truncated"] != true {
+		t.Errorf("Expected body_truncated flag")
+	}
+
+	// The real size is unknown, a size assertion can't pass on the cut body
+	m.Assertions = `[{"source":"size","operator":"<","target":"4096"}]`
+	result = p.Check(m)
+	if result.Success || !strings.Contains(result.Message, "not evaluable") {
+		t.Errorf("Expected size assertion to be not evaluable, got %v: %s", result.Success, result.Message)
+	}
+
+	m.Assertions = ""
+	m.Type = models.TypeHTTPKeyword
+	m.Keyword = "tail"
+	if result := p.Check(m); result.Success || !strings.Contains(result.Message, "truncated at 1024 bytes") {
+		t.Errorf("Expected keyword failure to mention the truncation, got %v: %s", result.Success, result.Message)
+	}
+}
+
+func TestHTTPProbe_Check_ContentChange(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headline := "Welcome"
+		if r.URL.Query().Get("defaced") != "" {
+			headline = "Hacked"
+		}
+		// The nonce changes on every request and must not count as a change
+		w.Write([]byte(`<html><body><input name="csrf" value="` + time.Now().String() + `">
+<main><h1>` + headline + `</h1></main></body></html>`))
+	}))
+	defer ts.Close()
+
+	p := probe.NewHTTPProbe()
+	m := models.Monitor{
+		Type:          models.TypeHTTP,
+		Target:        ts.URL,
+		Timeout:       1,
+		DetectChanges: true,
+		HashSelector:  "main",
+	}
+
+	// First check establishes the baseline
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	m.ContentHash = result.Data["content_hash"].(string)
+
+	if result = p.Check(m); !result.Success {
+		t.Fatalf("Expected unchanged content to pass, got failure: %s", result.Message)
+	}
+
+	m.Target = ts.URL + "?defaced=1"
+	result = p.Check(m)
+	if result.Success {
+		t.Fatalf("Expected failure due to content change, got success")
+	}
+	if result.Data["previous_hash"] != m.ContentHash {
+		t.Errorf("Expected previous hash in result data")
+	}
+}
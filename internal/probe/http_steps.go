@@ -0,0 +1,266 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"uptime_w33d/internal/models"
+)
+
+// HTTPStep is one request of a multi-step transaction (Monitor.Steps).
+// URL, Headers and Body may reference variables as {{name}}.
+type HTTPStep struct {
+	Name           string            `json:"name"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"` // Absolute, or relative to Monitor.Target
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	ExpectedStatus string            `json:"expected_status,omitempty"`
+	Assertions     []Assertion       `json:"assertions,omitempty"`
+	Extract        []Extraction      `json:"extract,omitempty"`
+}
+
+// Extraction stores a value from a step response into a variable.
+// Source is "json" (GJSON path), "header" (header name) or "regex"
+// (first capture group, or the whole match, on the body).
+type Extraction struct {
+	Var      string `json:"var"`
+	Source   string `json:"source"`
+	Property string `json:"property"`
+}
+
+// StepResult is the per-step report stored in Result.Data["steps"].
+type StepResult struct {
+	Name          string            `json:"name"`
+	Success       bool              `json:"success"`
+	StatusCode    int               `json:"status_code,omitempty"`
+	ResponseTime  int64             `json:"response_time"` // ms
+	Message       string            `json:"message,omitempty"`
+	Assertions    []AssertionResult `json:"assertions,omitempty"`
+	BodyTruncated bool              `json:"body_truncated,omitempty"` // Body cut at MaxBodySize
+}
+
+type HTTPStepsProbe struct {
+	BaseProbe
+}
+
+func NewHTTPStepsProbe() *HTTPStepsProbe {
+	return &HTTPStepsProbe{}
+}
+
+func (p *HTTPStepsProbe) Type() models.MonitorType {
+	return models.TypeHTTPSteps
+}
+
+func (p *HTTPStepsProbe) Check(monitor models.Monitor) Result {
+	var steps []HTTPStep
+	if err := json.Unmarshal([]byte(monitor.Steps), &steps); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid steps: %v", err), 0)
+	}
+	if len(steps) == 0 {
+		return p.RecordResult(false, "no steps defined", 0)
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	client, err := newHTTPClient(monitor, timeout)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+	// Keep session cookies between steps (e.g. login -> follow-up call)
+	client.Jar, _ = cookiejar.New(nil)
+
+	// Timeout covers the whole transaction, not each step
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Monitor headers go on every step, a step's own headers win
+	var headers map[string]string
+	if monitor.Headers != "" {
+		json.Unmarshal([]byte(monitor.Headers), &headers)
+	}
+
+	vars := make(map[string]string)
+	report := make([]StepResult, 0, len(steps))
+	var total time.Duration
+
+	for i, step := range steps {
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+
+		sr, elapsed := p.runStep(ctx, client, monitor, step, headers, vars)
+		total += elapsed
+		report = append(report, sr)
+
+		if !sr.Success {
+			res := p.RecordResult(false, fmt.Sprintf("Step '%s' failed: %s", step.Name, sr.Message), total)
+			res.Data["steps"] = report
+			res.Data["failed_step"] = step.Name
+			return res
+		}
+	}
+
+	res := p.RecordResult(true, fmt.Sprintf("All %d steps passed", len(steps)), total)
+	res.Data["steps"] = report
+	return res
+}
+
+func (p *HTTPStepsProbe) runStep(ctx context.Context, client *http.Client, monitor models.Monitor, step HTTPStep, headers map[string]string, vars map[string]string) (StepResult, time.Duration) {
+	sr := StepResult{Name: step.Name}
+
+	target, err := resolveStepURL(monitor.Target, expandVars(step.URL, vars))
+	if err != nil {
+		sr.Message = err.Error()
+		return sr, 0
+	}
+
+	method := step.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(expandVars(step.Body, vars)))
+	if err != nil {
+		sr.Message = fmt.Sprintf("invalid request: %v", err)
+		return sr, 0
+	}
+	req.Header.Set("User-Agent", "UptimeW33d/1.0")
+	for k, v := range headers {
+		req.Header.Set(k, expandVars(v, vars))
+	}
+	for k, v := range step.Headers {
+		req.Header.Set(k, expandVars(v, vars))
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		elapsed := time.Since(start)
+		sr.ResponseTime = elapsed.Milliseconds()
+		sr.Message = fmt.Sprintf("request failed: %v", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			sr.Message = fmt.Sprintf("transaction timed out after %ds", monitor.Timeout)
+		}
+		return sr, elapsed
+	}
+	defer resp.Body.Close()
+
+	body, truncated, err := readBody(resp.Body, monitor.MaxBodySize)
+	elapsed := time.Since(start)
+	sr.ResponseTime = elapsed.Milliseconds()
+	sr.StatusCode = resp.StatusCode
+	sr.BodyTruncated = truncated
+	if err != nil {
+		sr.Message = fmt.Sprintf("failed to read body: %v", err)
+		return sr, elapsed
+	}
+
+	ok, err := StatusMatches(resp.StatusCode, step.ExpectedStatus)
+	if err != nil {
+		sr.Message = fmt.Sprintf("invalid expected status: %v", err)
+		return sr, elapsed
+	}
+	if !ok {
+		expected := step.ExpectedStatus
+		if expected == "" {
+			expected = "200"
+		}
+		sr.Message = fmt.Sprintf("Unexpected status: %d (expected %s)", resp.StatusCode, expected)
+		return sr, elapsed
+	}
+
+	if len(step.Assertions) > 0 {
+		var failed *AssertionResult
+		sr.Assertions, failed = EvaluateAssertions(step.Assertions, resp, body, truncated)
+		if failed != nil {
+			sr.Message = fmt.Sprintf("Assertion failed: %s", failed.Message)
+			return sr, elapsed
+		}
+	}
+
+	for _, ex := range step.Extract {
+		val, err := extractValue(ex, resp, body)
+		if err != nil {
+			sr.Message = err.Error()
+			return sr, elapsed
+		}
+		vars[ex.Var] = val
+	}
+
+	sr.Success = true
+	return sr, elapsed
+}
+
+// expandVars replaces {{name}} placeholders with extracted values.
+func expandVars(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+	pairs := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// resolveStepURL resolves a step URL relative to the monitor target.
+func resolveStepURL(base, ref string) (string, error) {
+	if ref == "" {
+		return base, nil
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s': %v", ref, err)
+	}
+	if refURL.IsAbs() {
+		return ref, nil
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || !baseURL.IsAbs() {
+		return "", fmt.Errorf("relative URL '%s' needs an absolute monitor target", ref)
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+func extractValue(ex Extraction, resp *http.Response, body []byte) (string, error) {
+	if ex.Var == "" {
+		return "", fmt.Errorf("extraction without variable name")
+	}
+	switch ex.Source {
+	case "json":
+		res := gjson.GetBytes(body, ex.Property)
+		if !res.Exists() {
+			return "", fmt.Errorf("extract %s: JSON path '%s' not found", ex.Var, ex.Property)
+		}
+		return res.String(), nil
+	case "header":
+		val := resp.Header.Get(ex.Property)
+		if val == "" {
+			return "", fmt.Errorf("extract %s: header '%s' not found", ex.Var, ex.Property)
+		}
+		return val, nil
+	case "regex":
+		re, err := regexp.Compile(ex.Property)
+		if err != nil {
+			return "", fmt.Errorf("extract %s: invalid regex: %v", ex.Var, err)
+		}
+		m := re.FindSubmatch(body)
+		if m == nil {
+			return "", fmt.Errorf("extract %s: regex '%s' did not match", ex.Var, ex.Property)
+		}
+		if len(m) > 1 {
+			return string(m[1]), nil
+		}
+		return string(m[0]), nil
+	default:
+		return "", fmt.Errorf("extract %s: unknown source '%s'", ex.Var, ex.Source)
+	}
+}
@@ -0,0 +1,125 @@
+package probe_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func newTransactionServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Request-Id", "req-42")
+		w.Write([]byte(`{"token":"secret-token"}`))
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"user":"alice","trace":"` + r.Header.Get("X-Trace") + `","tenant":"` + r.Header.Get("X-Tenant") + `"}`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(600 * time.Millisecond)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestHTTPStepsProbe_Check_Success(t *testing.T) {
+	ts := newTransactionServer()
+	defer ts.Close()
+
+	p := probe.NewHTTPStepsProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTPSteps,
+		Target:  ts.URL,
+		Timeout: 1,
+		Headers: `{"X-Tenant":"acme","Authorization":"Bearer stale"}`,
+		Steps: `[
+			{"name":"login","method":"POST","url":"/login","body":"{}",
+			 "extract":[{"var":"token","source":"json","property":"token"},
+			            {"var":"rid","source":"header","property":"X-Request-Id"}]},
+			{"name":"profile","url":"/me",
+			 "headers":{"Authorization":"Bearer {{token}}","X-Trace":"{{rid}}"},
+			 "assertions":[{"source":"json","property":"user","operator":"==","target":"alice"},
+			               {"source":"json","property":"trace","operator":"==","target":"req-42"},
+			               {"source":"json","property":"tenant","operator":"==","target":"acme"}]}
+		]`,
+	}
+
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	steps, ok := result.Data["steps"].([]probe.StepResult)
+	if !ok || len(steps) != 2 {
+		t.Fatalf("Expected 2 step results, got %v", result.Data["steps"])
+	}
+	if steps[1].StatusCode != http.StatusOK {
+		t.Errorf("Expected profile step status 200, got %d", steps[1].StatusCode)
+	}
+}
+
+func TestHTTPStepsProbe_Check_FailingStep(t *testing.T) {
+	ts := newTransactionServer()
+	defer ts.Close()
+
+	p := probe.NewHTTPStepsProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTPSteps,
+		Target:  ts.URL,
+		Timeout: 1,
+		Steps: `[
+			{"name":"login","method":"POST","url":"/login",
+			 "extract":[{"var":"token","source":"regex","property":"\"token\":\"([^\"]+)\""}]},
+			{"name":"profile","url":"/me","headers":{"Authorization":"Bearer wrong-{{token}}"}},
+			{"name":"never-run","url":"/me"}
+		]`,
+	}
+
+	result := p.Check(m)
+	if result.Success {
+		t.Fatalf("Expected failure at profile step, got success")
+	}
+	if result.Data["failed_step"] != "profile" {
+		t.Errorf("Expected failed_step 'profile', got %v", result.Data["failed_step"])
+	}
+	steps := result.Data["steps"].([]probe.StepResult)
+	if len(steps) != 2 || steps[1].StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected run to stop after the 401 step, got %+v", steps)
+	}
+}
+
+func TestHTTPStepsProbe_Check_TransactionTimeout(t *testing.T) {
+	ts := newTransactionServer()
+	defer ts.Close()
+
+	// Each step fits in the timeout, the transaction does not
+	p := probe.NewHTTPStepsProbe()
+	m := models.Monitor{
+		Type:    models.TypeHTTPSteps,
+		Target:  ts.URL,
+		Timeout: 1,
+		Steps:   `[{"url":"/slow"},{"url":"/slow"},{"url":"/slow"}]`,
+	}
+
+	start := time.Now()
+	result := p.Check(m)
+	if result.Success {
+		t.Fatalf("Expected timeout failure, got success")
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("Expected the transaction to stop at the timeout, took %v", elapsed)
+	}
+	if result.Data["failed_step"] != "step 2" {
+		t.Errorf("Expected step 2 to time out, got %v: %s", result.Data["failed_step"], result.Message)
+	}
+}
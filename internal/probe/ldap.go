@@ -0,0 +1,131 @@
+package probe
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"uptime_w33d/internal/models"
+)
+
+type LDAPProbe struct {
+	BaseProbe
+}
+
+func NewLDAPProbe() *LDAPProbe {
+	return &LDAPProbe{}
+}
+
+func (p *LDAPProbe) Type() models.MonitorType {
+	return models.TypeLDAP
+}
+
+// Check connects to monitor.Target (ldap://, ldaps:// or host[:port] with
+// UseTLS), optionally upgrades with StartTLS, binds as Username/Password
+// (anonymous when empty) and, if SearchBase is set, searches it with the
+// Query filter expecting at least MinEntries results.
+func (p *LDAPProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	target := monitor.Target
+	if !strings.Contains(target, "://") {
+		scheme := "ldap"
+		if monitor.UseTLS {
+			scheme = "ldaps"
+		}
+		target = scheme + "://" + withDefaultPort(target, "389", "636", monitor.UseTLS)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid target: %v", err), 0)
+	}
+	host := u.Hostname()
+	if h, _, err := net.SplitHostPort(u.Host); err == nil {
+		host = h
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: !monitor.VerifyTLS, ServerName: host}
+
+	conn, err := ldap.DialURL(target,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+	connectTime := time.Since(start)
+
+	if monitor.StartTLS && u.Scheme != "ldaps" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("StartTLS failed: %v", err), time.Since(start))
+		}
+	}
+
+	bindStart := time.Now()
+	if monitor.Username != "" {
+		err = conn.Bind(monitor.Username, monitor.Password)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("bind failed: %v", err), time.Since(start))
+	}
+	bindTime := time.Since(bindStart)
+
+	msg := "Bind OK"
+	if monitor.Username == "" {
+		msg = "Anonymous bind OK"
+	}
+	success := true
+	entries := -1
+	var searchTime time.Duration
+
+	if monitor.SearchBase != "" {
+		filter := monitor.Query
+		if filter == "" {
+			filter = "(objectClass=*)"
+		}
+		// Only ask for as many entries as needed, big directories enforce
+		// their own size limit and answer sizeLimitExceeded otherwise
+		sizeLimit := 0
+		if monitor.MinEntries > 0 {
+			sizeLimit = monitor.MinEntries
+		}
+		searchStart := time.Now()
+		result, err := conn.Search(ldap.NewSearchRequest(
+			monitor.SearchBase, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			sizeLimit, monitor.Timeout, false, filter, []string{"1.1"}, nil,
+		))
+		searchTime = time.Since(searchStart)
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) && len(result.Entries) >= monitor.MinEntries {
+			err = nil
+		}
+		if err != nil {
+			return p.RecordResult(false, fmt.Sprintf("search failed: %v", err), time.Since(start))
+		}
+
+		entries = len(result.Entries)
+		msg = fmt.Sprintf("%s, %d entries", msg, entries)
+		if entries < monitor.MinEntries {
+			success = false
+			msg = fmt.Sprintf("Search returned %d entries (expected at least %d)", entries, monitor.MinEntries)
+		}
+	}
+
+	res := p.RecordResult(success, msg, time.Since(start))
+	res.Data["connect_ms"] = millis(connectTime)
+	res.Data["bind_ms"] = millis(bindTime)
+	if entries >= 0 {
+		res.Data["search_ms"] = millis(searchTime)
+		res.Data["entries"] = entries
+	}
+	if state, ok := conn.TLSConnectionState(); ok {
+		recordTLSState(state, res.Data)
+	}
+	return res
+}
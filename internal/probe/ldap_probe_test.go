@@ -0,0 +1,178 @@
+package probe_test
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// ldapResult builds an LDAPMessage carrying an LDAPResult-shaped response.
+func ldapResult(id int64, app ber.Tag, code int64) *ber.Packet {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, app, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	msg.AppendChild(op)
+	return msg
+}
+
+// startLDAPServer is a tiny directory: bind as cn=monitor,dc=example,dc=org
+// with "s3cret", StartTLS, and a search under ou=people returning 3 entries.
+func startLDAPServer(t *testing.T, implicitTLS bool) string {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsConfig := &tls.Config{Certificates: ts.TLS.Certificates}
+	ts.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	if implicitTLS {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer func() { c.Close() }()
+				for {
+					packet, err := ber.ReadPacket(c)
+					if err != nil || len(packet.Children) < 2 {
+						return
+					}
+					id := packet.Children[0].Value.(int64)
+					op := packet.Children[1]
+					switch op.Tag {
+					case 0: // Bind
+						code := int64(49) // invalidCredentials
+						name, password := op.Children[1].Value, op.Children[2].Data.String()
+						if name == "cn=monitor,dc=example,dc=org" && password == "s3cret" || name == "" && password == "" {
+							code = 0
+						}
+						c.Write(ldapResult(id, 1, code).Bytes())
+					case 3: // Search
+						code := int64(32) // noSuchObject
+						if op.Children[0].Value == "ou=people,dc=example,dc=org" {
+							code = 0
+							uids := []string{"ada", "grace", "linus"}
+							if limit := int(op.Children[3].Value.(int64)); limit > 0 && limit < len(uids) {
+								uids = uids[:limit]
+								code = 4 // sizeLimitExceeded
+							}
+							for _, uid := range uids {
+								msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+								msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+								entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, 4, nil, "")
+								entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "uid="+uid+",ou=people,dc=example,dc=org", ""))
+								entry.AppendChild(ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, ""))
+								msg.AppendChild(entry)
+								c.Write(msg.Bytes())
+							}
+						}
+						c.Write(ldapResult(id, 5, code).Bytes())
+					case 23: // Extended: StartTLS
+						c.Write(ldapResult(id, 24, 0).Bytes())
+						c = tls.Server(c, tlsConfig)
+					default: // Unbind
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestLDAPProbe_Check_BindAndSearch(t *testing.T) {
+	addr := startLDAPServer(t, false)
+
+	p := probe.NewLDAPProbe()
+	m := models.Monitor{
+		Type:       models.TypeLDAP,
+		Target:     "ldap://" + addr,
+		Timeout:    2,
+		Username:   "cn=monitor,dc=example,dc=org",
+		Password:   "s3cret",
+		SearchBase: "ou=people,dc=example,dc=org",
+		Query:      "(objectClass=person)",
+		MinEntries: 3,
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["entries"] != 3 {
+		t.Errorf("Expected 3 entries, got %v", result.Data["entries"])
+	}
+
+	m.MinEntries = 2
+	result = p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success when the size limit is hit, got failure: %s", result.Message)
+	}
+	if result.Data["entries"] != 2 {
+		t.Errorf("Expected 2 entries, got %v", result.Data["entries"])
+	}
+
+	m.MinEntries = 5
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure below min entries, got success")
+	}
+
+	m.MinEntries = 0
+	m.StartTLS = true
+	result = p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected StartTLS success, got failure: %s", result.Message)
+	}
+	if _, ok := result.Data["tls_version"]; !ok {
+		t.Errorf("Expected TLS details after StartTLS, got %v", result.Data)
+	}
+
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected bind failure, got success")
+	}
+
+	m.Username, m.Password = "", ""
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected anonymous bind success, got failure: %s", result.Message)
+	}
+}
+
+func TestLDAPProbe_Check_LDAPS(t *testing.T) {
+	addr := startLDAPServer(t, true)
+
+	p := probe.NewLDAPProbe()
+	m := models.Monitor{
+		Type:     models.TypeLDAP,
+		Target:   addr,
+		UseTLS:   true,
+		Timeout:  2,
+		Username: "cn=monitor,dc=example,dc=org",
+		Password: "s3cret",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Fatalf("Expected LDAPS bind success, got failure: %s", result.Message)
+	}
+
+	m.VerifyTLS = true
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected certificate verification failure, got success")
+	}
+}
@@ -0,0 +1,341 @@
+package probe
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+// --- SMTP ---
+
+type SMTPProbe struct {
+	BaseProbe
+}
+
+func NewSMTPProbe() *SMTPProbe {
+	return &SMTPProbe{}
+}
+
+func (p *SMTPProbe) Type() models.MonitorType {
+	return models.TypeSMTP
+}
+
+// Check reads the 220 greeting, sends EHLO, optionally upgrades with
+// STARTTLS (or uses implicit TLS with UseTLS) and authenticates when
+// credentials are set.
+func (p *SMTPProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	addr := withDefaultPort(monitor.Target, "25", "465", monitor.UseTLS)
+	host, _, _ := net.SplitHostPort(addr)
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: !monitor.VerifyTLS}
+
+	conn, err := dialMail(addr, monitor.UseTLS, tlsConfig, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// NewClient validates the 220 greeting
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("bad greeting: %v", err), time.Since(start))
+	}
+	defer client.Close()
+
+	if err := client.Hello("uptime-w33d.local"); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("EHLO failed: %v", err), time.Since(start))
+	}
+
+	if monitor.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return p.RecordResult(false, "server does not offer STARTTLS", time.Since(start))
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("STARTTLS failed: %v", err), time.Since(start))
+		}
+	}
+
+	if monitor.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return p.RecordResult(false, "server does not offer AUTH", time.Since(start))
+		}
+		if err := client.Auth(smtp.PlainAuth("", monitor.Username, monitor.Password, host)); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("AUTH failed: %v", err), time.Since(start))
+		}
+	}
+
+	duration := time.Since(start)
+	client.Quit()
+
+	res := p.RecordResult(true, "SMTP OK", duration)
+	if state, ok := client.TLSConnectionState(); ok {
+		recordTLSState(state, res.Data)
+	}
+	return res
+}
+
+// --- IMAP ---
+
+type IMAPProbe struct {
+	BaseProbe
+}
+
+func NewIMAPProbe() *IMAPProbe {
+	return &IMAPProbe{}
+}
+
+func (p *IMAPProbe) Type() models.MonitorType {
+	return models.TypeIMAP
+}
+
+// Check reads the "* OK" greeting, runs CAPABILITY, optionally STARTTLS
+// and LOGIN, then logs out.
+func (p *IMAPProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	addr := withDefaultPort(monitor.Target, "143", "993", monitor.UseTLS)
+	host, _, _ := net.SplitHostPort(addr)
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: !monitor.VerifyTLS}
+
+	conn, err := dialMail(addr, monitor.UseTLS, tlsConfig, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	tp := textproto.NewConn(conn)
+	greeting, err := tp.ReadLine()
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("no greeting: %v", err), time.Since(start))
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		return p.RecordResult(false, fmt.Sprintf("bad greeting: %s", greeting), time.Since(start))
+	}
+
+	caps, err := imapCommand(tp, "a1", "CAPABILITY")
+	if err != nil {
+		return p.RecordResult(false, err.Error(), time.Since(start))
+	}
+
+	if monitor.StartTLS {
+		if !strings.Contains(caps, "STARTTLS") {
+			return p.RecordResult(false, "server does not offer STARTTLS", time.Since(start))
+		}
+		if _, err := imapCommand(tp, "a2", "STARTTLS"); err != nil {
+			return p.RecordResult(false, err.Error(), time.Since(start))
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("STARTTLS failed: %v", err), time.Since(start))
+		}
+		conn = tlsConn
+		tp = textproto.NewConn(conn)
+		// Capabilities must be re-read after the upgrade
+		if caps, err = imapCommand(tp, "a3", "CAPABILITY"); err != nil {
+			return p.RecordResult(false, err.Error(), time.Since(start))
+		}
+	}
+
+	if monitor.Username != "" {
+		if _, ok := conn.(*tls.Conn); !ok {
+			return p.RecordResult(false, errCleartextLogin, time.Since(start))
+		}
+		login := fmt.Sprintf("LOGIN %s %s", imapQuote(monitor.Username), imapQuote(monitor.Password))
+		if _, err := imapCommand(tp, "a4", login); err != nil {
+			return p.RecordResult(false, "LOGIN failed: "+strings.TrimPrefix(err.Error(), "LOGIN "), time.Since(start))
+		}
+	}
+
+	duration := time.Since(start)
+	imapCommand(tp, "a5", "LOGOUT")
+
+	res := p.RecordResult(true, "IMAP OK", duration)
+	res.Data["capabilities"] = caps
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		recordTLSState(tlsConn.ConnectionState(), res.Data)
+	}
+	return res
+}
+
+// imapCommand sends a tagged command and returns the untagged CAPABILITY data, if any.
+func imapCommand(tp *textproto.Conn, tag, cmd string) (string, error) {
+	verb := strings.Fields(cmd)[0]
+	if err := tp.PrintfLine("%s %s", tag, cmd); err != nil {
+		return "", fmt.Errorf("%s failed: %v", verb, err)
+	}
+	var caps string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return "", fmt.Errorf("%s failed: %v", verb, err)
+		}
+		if strings.HasPrefix(line, "* CAPABILITY ") {
+			caps = strings.TrimPrefix(line, "* CAPABILITY ")
+			continue
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return "", fmt.Errorf("%s %s", verb, status)
+			}
+			return caps, nil
+		}
+	}
+}
+
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// --- POP3 ---
+
+type POP3Probe struct {
+	BaseProbe
+}
+
+func NewPOP3Probe() *POP3Probe {
+	return &POP3Probe{}
+}
+
+func (p *POP3Probe) Type() models.MonitorType {
+	return models.TypePOP3
+}
+
+// Check reads the "+OK" greeting, runs CAPA, optionally STLS and USER/PASS, then quits.
+func (p *POP3Probe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	addr := withDefaultPort(monitor.Target, "110", "995", monitor.UseTLS)
+	host, _, _ := net.SplitHostPort(addr)
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: !monitor.VerifyTLS}
+
+	conn, err := dialMail(addr, monitor.UseTLS, tlsConfig, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	tp := textproto.NewConn(conn)
+	greeting, err := tp.ReadLine()
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("no greeting: %v", err), time.Since(start))
+	}
+	if !strings.HasPrefix(greeting, "+OK") {
+		return p.RecordResult(false, fmt.Sprintf("bad greeting: %s", greeting), time.Since(start))
+	}
+
+	// CAPA is optional in POP3, a -ERR just means no capability list
+	caps, _ := pop3Capa(tp)
+
+	if monitor.StartTLS {
+		if _, err := pop3Command(tp, "STLS"); err != nil {
+			return p.RecordResult(false, err.Error(), time.Since(start))
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("STLS failed: %v", err), time.Since(start))
+		}
+		conn = tlsConn
+		tp = textproto.NewConn(conn)
+	}
+
+	if monitor.Username != "" {
+		if _, ok := conn.(*tls.Conn); !ok {
+			return p.RecordResult(false, errCleartextLogin, time.Since(start))
+		}
+		if _, err := pop3Command(tp, "USER "+monitor.Username); err != nil {
+			return p.RecordResult(false, "login failed: "+err.Error(), time.Since(start))
+		}
+		if _, err := pop3Command(tp, "PASS "+monitor.Password); err != nil {
+			return p.RecordResult(false, "login failed: PASS rejected", time.Since(start))
+		}
+	}
+
+	duration := time.Since(start)
+	pop3Command(tp, "QUIT")
+
+	res := p.RecordResult(true, "POP3 OK", duration)
+	if len(caps) > 0 {
+		res.Data["capabilities"] = strings.Join(caps, " ")
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		recordTLSState(tlsConn.ConnectionState(), res.Data)
+	}
+	return res
+}
+
+func pop3Command(tp *textproto.Conn, cmd string) (string, error) {
+	if err := tp.PrintfLine("%s", cmd); err != nil {
+		return "", err
+	}
+	line, err := tp.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return "", fmt.Errorf("%s: %s", strings.Fields(cmd)[0], line)
+	}
+	return line, nil
+}
+
+func pop3Capa(tp *textproto.Conn) ([]string, error) {
+	if _, err := pop3Command(tp, "CAPA"); err != nil {
+		return nil, err
+	}
+	lines, err := tp.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// --- Helpers ---
+
+// errCleartextLogin is reported instead of sending IMAP LOGIN or POP3
+// USER/PASS over an unencrypted connection (SMTP's PlainAuth refuses too).
+const errCleartextLogin = "refusing to send credentials without TLS, enable TLS or STARTTLS"
+
+// withDefaultPort appends the protocol's default (or implicit TLS) port when target has none.
+func withDefaultPort(target, plainPort, tlsPort string, useTLS bool) string {
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target
+	}
+	if useTLS {
+		return net.JoinHostPort(target, tlsPort)
+	}
+	return net.JoinHostPort(target, plainPort)
+}
+
+func dialMail(addr string, useTLS bool, tlsConfig *tls.Config, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if useTLS {
+		return tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+// recordTLSState stores the peer certificate details. "cert_expiry" is picked
+// up by the scheduler to update Monitor.CertificateExpiry.
+func recordTLSState(state tls.ConnectionState, data map[string]interface{}) {
+	data["tls_version"] = tls.VersionName(state.Version)
+	if len(state.PeerCertificates) == 0 {
+		return
+	}
+	cert := state.PeerCertificates[0]
+	data["cert_expiry"] = cert.NotAfter
+	data["cert_subject"] = cert.Subject.CommonName
+	data["cert_issuer"] = cert.Issuer.CommonName
+}
@@ -0,0 +1,206 @@
+package probe_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// mailSession scripts one side of a line protocol. handle returns the reply
+// lines for a command and whether the connection should switch to TLS.
+type mailSession func(line string) (reply []string, startTLS bool)
+
+func startMailServer(t *testing.T, greeting string, handle mailSession) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	// Borrow the self-signed test certificate for STARTTLS
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsConfig := &tls.Config{Certificates: ts.TLS.Certificates}
+	ts.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer func() { c.Close() }()
+				c.Write([]byte(greeting + "\r\n"))
+				r := bufio.NewReader(c)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					reply, upgrade := handle(strings.TrimRight(line, "\r\n"))
+					for _, l := range reply {
+						c.Write([]byte(l + "\r\n"))
+					}
+					if upgrade {
+						c = tls.Server(c, tlsConfig)
+						r = bufio.NewReader(c)
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSMTPProbe_Check(t *testing.T) {
+	wantAuth := base64.StdEncoding.EncodeToString([]byte("\x00alerts@example.com\x00hunter2"))
+	addr := startMailServer(t, "220 mail.example.com ESMTP", func(line string) ([]string, bool) {
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			return []string{"250-mail.example.com", "250-AUTH PLAIN", "250 8BITMIME"}, false
+		case strings.HasPrefix(line, "AUTH PLAIN"):
+			if strings.TrimPrefix(line, "AUTH PLAIN ") == wantAuth {
+				return []string{"235 2.7.0 Authentication successful"}, false
+			}
+			return []string{"535 5.7.8 Authentication failed"}, false
+		case line == "QUIT":
+			return []string{"221 Bye"}, false
+		}
+		return []string{"502 Command not implemented"}, false
+	})
+
+	p := probe.NewSMTPProbe()
+	m := models.Monitor{Type: models.TypeSMTP, Target: addr, Timeout: 1}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected EHLO success, got failure: %s", result.Message)
+	}
+
+	m.Username = "alerts@example.com"
+	m.Password = "hunter2"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected AUTH success, got failure: %s", result.Message)
+	}
+
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected AUTH failure, got success")
+	}
+
+	m.Password = "hunter2"
+	m.StartTLS = true
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure when STARTTLS is not offered, got success")
+	}
+}
+
+func TestSMTPProbe_Check_BadGreeting(t *testing.T) {
+	addr := startMailServer(t, "554 No SMTP service here", func(string) ([]string, bool) { return nil, false })
+
+	p := probe.NewSMTPProbe()
+	m := models.Monitor{Type: models.TypeSMTP, Target: addr, Timeout: 1}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for 554 greeting, got success")
+	}
+}
+
+func TestIMAPProbe_Check_StartTLSLogin(t *testing.T) {
+	addr := startMailServer(t, "* OK IMAP4rev1 ready", func(line string) ([]string, bool) {
+		tag, cmd, _ := strings.Cut(line, " ")
+		switch {
+		case cmd == "CAPABILITY":
+			return []string{"* CAPABILITY IMAP4rev1 STARTTLS AUTH=PLAIN", tag + " OK CAPABILITY completed"}, false
+		case cmd == "STARTTLS":
+			return []string{tag + " OK Begin TLS negotiation now"}, true
+		case cmd == `LOGIN "alice" "s3cret"`:
+			return []string{tag + " OK LOGIN completed"}, false
+		case strings.HasPrefix(cmd, "LOGIN"):
+			return []string{tag + " NO [AUTHENTICATIONFAILED] Invalid credentials"}, false
+		case cmd == "LOGOUT":
+			return []string{"* BYE", tag + " OK LOGOUT completed"}, false
+		}
+		return []string{tag + " BAD unknown command"}, false
+	})
+
+	p := probe.NewIMAPProbe()
+	m := models.Monitor{
+		Type:     models.TypeIMAP,
+		Target:   addr,
+		Timeout:  1,
+		StartTLS: true,
+		Username: "alice",
+		Password: "s3cret",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if _, ok := result.Data["cert_expiry"]; !ok {
+		t.Errorf("Expected certificate details after STARTTLS")
+	}
+
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected LOGIN failure, got success")
+	}
+
+	m.Password = "s3cret"
+	m.StartTLS = false
+	if result := p.Check(m); result.Success || !strings.Contains(result.Message, "without TLS") {
+		t.Errorf("Expected cleartext LOGIN to be refused, got %v: %s", result.Success, result.Message)
+	}
+}
+
+func TestPOP3Probe_Check(t *testing.T) {
+	addr := startMailServer(t, "+OK POP3 server ready", func(line string) ([]string, bool) {
+		switch line {
+		case "CAPA":
+			return []string{"+OK Capability list follows", "USER", "UIDL", "STLS", "."}, false
+		case "STLS":
+			return []string{"+OK Begin TLS negotiation"}, true
+		case "USER bob":
+			return []string{"+OK"}, false
+		case "PASS letmein":
+			return []string{"+OK Logged in"}, false
+		case "QUIT":
+			return []string{"+OK Bye"}, false
+		}
+		return []string{"-ERR nope"}, false
+	})
+
+	p := probe.NewPOP3Probe()
+	m := models.Monitor{
+		Type:     models.TypePOP3,
+		Target:   addr,
+		Timeout:  1,
+		StartTLS: true,
+		Username: "bob",
+		Password: "letmein",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["capabilities"] != "USER UIDL STLS" {
+		t.Errorf("Expected capabilities 'USER UIDL STLS', got %v", result.Data["capabilities"])
+	}
+
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected login failure, got success")
+	}
+
+	m.Password = "letmein"
+	m.StartTLS = false
+	if result := p.Check(m); result.Success || !strings.Contains(result.Message, "without TLS") {
+		t.Errorf("Expected cleartext login to be refused, got %v: %s", result.Success, result.Message)
+	}
+}
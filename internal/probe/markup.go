@@ -0,0 +1,99 @@
+package probe
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+)
+
+// queryMarkup evaluates a "css" or "xpath" assertion against an HTML/XML body.
+// It returns the text of the first matching element, or the value of
+// a.Attribute when set, and whether anything matched.
+func queryMarkup(a Assertion, resp *http.Response, body []byte) (string, bool, error) {
+	if a.Property == "" {
+		return "", false, fmt.Errorf("%s assertion requires a selector", a.Source)
+	}
+
+	switch a.Source {
+	case "css":
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse HTML: %v", err)
+		}
+		sel := doc.Find(a.Property).First()
+		if sel.Length() == 0 {
+			return "", false, nil
+		}
+		if a.Attribute != "" {
+			val, ok := sel.Attr(a.Attribute)
+			return val, ok, nil
+		}
+		return strings.TrimSpace(sel.Text()), true, nil
+
+	case "xpath":
+		if isXML(resp, body) {
+			doc, err := xmlquery.Parse(bytes.NewReader(body))
+			if err != nil {
+				return "", false, fmt.Errorf("failed to parse XML: %v", err)
+			}
+			node, err := xmlquery.Query(doc, a.Property)
+			if err != nil {
+				return "", false, fmt.Errorf("invalid xpath '%s': %v", a.Property, err)
+			}
+			if node == nil {
+				return "", false, nil
+			}
+			if a.Attribute != "" {
+				for _, attr := range node.Attr {
+					if attr.Name.Local == a.Attribute {
+						return attr.Value, true, nil
+					}
+				}
+				return "", false, nil
+			}
+			return strings.TrimSpace(node.InnerText()), true, nil
+		}
+
+		doc, err := htmlquery.Parse(bytes.NewReader(body))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse HTML: %v", err)
+		}
+		node, err := htmlquery.Query(doc, a.Property)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid xpath '%s': %v", a.Property, err)
+		}
+		if node == nil {
+			return "", false, nil
+		}
+		if a.Attribute != "" {
+			for _, attr := range node.Attr {
+				if attr.Key == a.Attribute {
+					return attr.Val, true, nil
+				}
+			}
+			return "", false, nil
+		}
+		return strings.TrimSpace(htmlquery.InnerText(node)), true, nil
+	}
+
+	return "", false, fmt.Errorf("unknown markup source '%s'", a.Source)
+}
+
+// isXML decides whether the body should be parsed as XML rather than HTML.
+func isXML(resp *http.Response, body []byte) bool {
+	if resp != nil {
+		ct := strings.ToLower(resp.Header.Get("Content-Type"))
+		if strings.Contains(ct, "xml") && !strings.Contains(ct, "xhtml") {
+			return true
+		}
+		if strings.Contains(ct, "html") {
+			return false
+		}
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<?xml"))
+}
@@ -0,0 +1,323 @@
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+const (
+	minecraftJavaPort    = "25565"
+	minecraftBedrockPort = "19132"
+	maxMinecraftPacket   = 1 << 20
+)
+
+// bedrockMagic is the RakNet "offline message" marker.
+var bedrockMagic = []byte{0x00, 0xff, 0xff, 0x00, 0xfe, 0xfe, 0xfe, 0xfe, 0xfd, 0xfd, 0xfd, 0xfd, 0x12, 0x34, 0x56, 0x78}
+
+// formattingCodes matches the section sign colour/style codes in MOTDs.
+var formattingCodes = regexp.MustCompile(`§.`)
+
+type MinecraftProbe struct {
+	BaseProbe
+}
+
+func NewMinecraftProbe() *MinecraftProbe {
+	return &MinecraftProbe{}
+}
+
+func (p *MinecraftProbe) Type() models.MonitorType {
+	return models.TypeMinecraft
+}
+
+// minecraftStatus is what both editions report.
+type minecraftStatus struct {
+	Version  string
+	Protocol int
+	MOTD     string
+	Online   int
+	Max      int
+	Extra    map[string]interface{}
+}
+
+// Check runs the Java Server List Ping, or the Bedrock unconnected ping when
+// Edition is "bedrock", and applies the same player checks as the Steam probe.
+// ServerNamePattern is matched against the MOTD.
+func (p *MinecraftProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	var status *minecraftStatus
+	var err error
+	switch monitor.Edition {
+	case "bedrock":
+		status, err = pingBedrock(monitor.Target, timeout)
+	case "", "java":
+		status, err = pingJava(monitor.Target, timeout)
+	default:
+		return p.RecordResult(false, fmt.Sprintf("unknown edition: %s", monitor.Edition), 0)
+	}
+	duration := time.Since(start)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("status query failed: %v", err), duration)
+	}
+
+	msg := fmt.Sprintf("%s (%d/%d players)", truncate(status.MOTD, 60), status.Online, status.Max)
+	success, degraded := true, false
+
+	if monitor.ServerNamePattern != "" {
+		re, err := regexp.Compile(monitor.ServerNamePattern)
+		if err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid server name pattern: %v", err), duration)
+		}
+		if !re.MatchString(status.MOTD) {
+			success = false
+			msg = fmt.Sprintf("MOTD '%s' does not match '%s'", truncate(status.MOTD, 60), monitor.ServerNamePattern)
+		}
+	}
+
+	population := populationState(status.Online, status.Max)
+	if success {
+		success, degraded, msg = checkPlayers(monitor, status.Online, status.Max, population, msg)
+	}
+
+	res := p.RecordResult(success, msg, duration)
+	res.Degraded = degraded
+	res.Data["version"] = status.Version
+	res.Data["protocol"] = status.Protocol
+	res.Data["motd"] = status.MOTD
+	res.Data["players"] = status.Online
+	res.Data["max_players"] = status.Max
+	res.Data["population"] = population
+	for k, v := range status.Extra {
+		res.Data[k] = v
+	}
+	return res
+}
+
+// pingJava speaks the Server List Ping: handshake (next state 1), status
+// request, JSON status response. A bare host is resolved via the
+// _minecraft._tcp SRV record like the game client does.
+func pingJava(target string, timeout time.Duration) (*minecraftStatus, error) {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		host, port = target, minecraftJavaPort
+		if _, srvs, err := net.LookupSRV("minecraft", "tcp", host); err == nil && len(srvs) > 0 {
+			host = strings.TrimSuffix(srvs[0].Target, ".")
+			port = strconv.Itoa(int(srvs[0].Port))
+		}
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port: %s", port)
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, 0x00) // Handshake
+	writeVarInt(&handshake, -1)   // Protocol version: unknown
+	writeVarInt(&handshake, len(host))
+	handshake.WriteString(host)
+	binary.Write(&handshake, binary.BigEndian, uint16(portNum))
+	writeVarInt(&handshake, 1) // Next state: status
+
+	var out bytes.Buffer
+	writePacket(&out, handshake.Bytes())
+	writePacket(&out, []byte{0x00}) // Status request
+	if _, err := conn.Write(out.Bytes()); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	length, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || length > maxMinecraftPacket {
+		return nil, fmt.Errorf("invalid packet length %d", length)
+	}
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return nil, err
+	}
+
+	pr := bytes.NewReader(packet)
+	if id, err := readVarInt(pr); err != nil || id != 0x00 {
+		return nil, errors.New("unexpected status response")
+	}
+	size, err := readVarInt(pr)
+	if err != nil || size < 0 || size > pr.Len() {
+		return nil, errors.New("malformed status response")
+	}
+	raw := make([]byte, size)
+	pr.Read(raw)
+
+	var resp struct {
+		Version struct {
+			Name     string `json:"name"`
+			Protocol int    `json:"protocol"`
+		} `json:"version"`
+		Players struct {
+			Max    int `json:"max"`
+			Online int `json:"online"`
+			Sample []struct {
+				Name string `json:"name"`
+			} `json:"sample"`
+		} `json:"players"`
+		Description json.RawMessage `json:"description"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("invalid status JSON: %w", err)
+	}
+
+	status := &minecraftStatus{
+		Version:  resp.Version.Name,
+		Protocol: resp.Version.Protocol,
+		MOTD:     chatText(resp.Description),
+		Online:   resp.Players.Online,
+		Max:      resp.Players.Max,
+		Extra:    map[string]interface{}{"edition": "java"},
+	}
+	if len(resp.Players.Sample) > 0 {
+		names := make([]string, 0, len(resp.Players.Sample))
+		for _, s := range resp.Players.Sample {
+			names = append(names, s.Name)
+		}
+		status.Extra["player_sample"] = names
+	}
+	return status, nil
+}
+
+// pingBedrock sends a RakNet unconnected ping and parses the semicolon
+// separated server ID string of the pong.
+func pingBedrock(target string, timeout time.Duration) (*minecraftStatus, error) {
+	addr := withDefaultPort(target, minecraftBedrockPort, minecraftBedrockPort, false)
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var ping bytes.Buffer
+	ping.WriteByte(0x01) // Unconnected ping
+	binary.Write(&ping, binary.BigEndian, time.Now().UnixMilli())
+	ping.Write(bedrockMagic)
+	binary.Write(&ping, binary.BigEndian, uint64(time.Now().UnixNano()))
+	if _, err := conn.Write(ping.Bytes()); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 2048)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	// 0x1c, time (8), server GUID (8), magic (16), string length (2), server ID
+	if n < 35 || buf[0] != 0x1c || !bytes.Equal(buf[17:33], bedrockMagic) {
+		return nil, errors.New("unexpected pong")
+	}
+	size := int(binary.BigEndian.Uint16(buf[33:35]))
+	if 35+size > n {
+		return nil, errors.New("truncated pong")
+	}
+
+	// MCPE;MOTD;protocol;version;online;max;server id;level name;game mode;...
+	fields := strings.Split(string(buf[35:35+size]), ";")
+	if len(fields) < 6 {
+		return nil, errors.New("malformed server ID string")
+	}
+	protocol, _ := strconv.Atoi(fields[2])
+	online, _ := strconv.Atoi(fields[4])
+	max, _ := strconv.Atoi(fields[5])
+
+	status := &minecraftStatus{
+		Version:  fields[3],
+		Protocol: protocol,
+		MOTD:     formattingCodes.ReplaceAllString(fields[1], ""),
+		Online:   online,
+		Max:      max,
+		Extra:    map[string]interface{}{"edition": fields[0]},
+	}
+	if len(fields) > 7 {
+		status.Extra["level_name"] = fields[7]
+	}
+	if len(fields) > 8 {
+		status.Extra["game_mode"] = fields[8]
+	}
+	return status, nil
+}
+
+// chatText flattens a chat component (plain string or {"text", "extra"}) to
+// plain text without formatting codes.
+func chatText(raw json.RawMessage) string {
+	var b strings.Builder
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch c := v.(type) {
+		case string:
+			b.WriteString(c)
+		case []interface{}:
+			for _, e := range c {
+				walk(e)
+			}
+		case map[string]interface{}:
+			walk(c["text"])
+			walk(c["extra"])
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err == nil {
+		walk(v)
+	}
+	return strings.TrimSpace(formattingCodes.ReplaceAllString(b.String(), ""))
+}
+
+func writePacket(w *bytes.Buffer, payload []byte) {
+	writeVarInt(w, len(payload))
+	w.Write(payload)
+}
+
+func writeVarInt(w *bytes.Buffer, v int) {
+	u := uint32(v)
+	for {
+		if u&^0x7f == 0 {
+			w.WriteByte(byte(u))
+			return
+		}
+		w.WriteByte(byte(u&0x7f | 0x80))
+		u >>= 7
+	}
+}
+
+func readVarInt(r io.ByteReader) (int, error) {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int(int32(v)), nil
+		}
+	}
+	return 0, errors.New("varint too long")
+}
@@ -0,0 +1,155 @@
+package probe_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func putVarInt(b *bytes.Buffer, v int) {
+	u := uint32(v)
+	for u&^0x7f != 0 {
+		b.WriteByte(byte(u&0x7f | 0x80))
+		u >>= 7
+	}
+	b.WriteByte(byte(u))
+}
+
+func getVarInt(r io.ByteReader) int {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b, _ := r.ReadByte()
+		v |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return int(int32(v))
+}
+
+// startJavaServer answers the Server List Ping with a fixed status JSON.
+func startJavaServer(t *testing.T, status string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				// Handshake, then status request
+				for i := 0; i < 2; i++ {
+					n := getVarInt(r)
+					if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+						return
+					}
+				}
+				var payload, packet bytes.Buffer
+				putVarInt(&payload, 0x00)
+				putVarInt(&payload, len(status))
+				payload.WriteString(status)
+				putVarInt(&packet, payload.Len())
+				packet.Write(payload.Bytes())
+				conn.Write(packet.Bytes())
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// startBedrockServer answers RakNet unconnected pings.
+func startBedrockServer(t *testing.T, serverID string) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 33 || buf[0] != 0x01 {
+				continue
+			}
+			var pong bytes.Buffer
+			pong.WriteByte(0x1c)
+			pong.Write(buf[1:9])                                    // echo time
+			binary.Write(&pong, binary.BigEndian, uint64(0xC0FFEE)) // server GUID
+			pong.Write(buf[9:25])                                   // magic
+			binary.Write(&pong, binary.BigEndian, uint16(len(serverID)))
+			pong.WriteString(serverID)
+			conn.WriteTo(pong.Bytes(), addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestMinecraftProbe_Check_Java(t *testing.T) {
+	addr := startJavaServer(t, `{"version":{"name":"1.21.4","protocol":769},`+
+		`"players":{"max":20,"online":3,"sample":[{"name":"Notch","id":"069a79f4-44e9-4726-a5be-fca90e38aaf5"}]},`+
+		`"description":{"text":"§aW33d ","extra":[{"text":"Survival"}]}}`)
+
+	p := probe.NewMinecraftProbe()
+	m := models.Monitor{
+		Type:              models.TypeMinecraft,
+		Target:            addr,
+		Timeout:           1,
+		MinPlayers:        1,
+		ServerNamePattern: "Survival$",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["motd"] != "W33d Survival" || result.Data["version"] != "1.21.4" || result.Data["players"] != 3 {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+
+	m.MinPlayers = 5
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure below min players, got success")
+	}
+}
+
+func TestMinecraftProbe_Check_Bedrock(t *testing.T) {
+	addr := startBedrockServer(t, "MCPE;§lW33d Bedrock;766;1.21.50;0;10;13253860892328930865;Bedrock level;Survival;1;19132;19133;")
+
+	p := probe.NewMinecraftProbe()
+	m := models.Monitor{
+		Type:    models.TypeMinecraft,
+		Target:  addr,
+		Edition: "bedrock",
+		Timeout: 1,
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["motd"] != "W33d Bedrock" || result.Data["max_players"] != 10 || result.Data["population"] != "empty" {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+
+	m.Edition = "java"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected Java ping against a Bedrock server to fail, got success")
+	}
+}
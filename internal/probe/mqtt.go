@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"uptime_w33d/internal/models"
+)
+
+type MQTTProbe struct {
+	BaseProbe
+}
+
+func NewMQTTProbe() *MQTTProbe {
+	return &MQTTProbe{}
+}
+
+func (p *MQTTProbe) Type() models.MonitorType {
+	return models.TypeMQTT
+}
+
+// Check connects to the broker in monitor.Target (tcp://, ssl://, ws:// or wss://).
+// With a Topic it either round-trips a probe message (publish, expect it back),
+// or, when ExpectedValue is set, waits for the retained message and compares it.
+func (p *MQTTProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	broker := monitor.Target
+	if !strings.Contains(broker, "://") {
+		broker = "tcp://" + broker
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(fmt.Sprintf("uptime-w33d-%d-%d", monitor.ID, time.Now().UnixNano())).
+		SetConnectTimeout(timeout).
+		SetWriteTimeout(timeout).
+		SetAutoReconnect(false).
+		SetConnectRetry(false).
+		SetCleanSession(true).
+		SetTLSConfig(&tls.Config{InsecureSkipVerify: !monitor.VerifyTLS})
+	if monitor.Username != "" {
+		opts.SetUsername(monitor.Username)
+		opts.SetPassword(monitor.Password)
+	}
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(timeout) {
+		return p.RecordResult(false, "connect timed out", time.Since(start))
+	}
+	if err := token.Error(); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connect failed: %v", err), time.Since(start))
+	}
+	defer client.Disconnect(100)
+
+	if monitor.Topic == "" {
+		return p.RecordResult(true, "Connected", time.Since(start))
+	}
+
+	retained := monitor.ExpectedValue != ""
+	probeMsg := monitor.SendPayload
+	if probeMsg == "" {
+		probeMsg = fmt.Sprintf("uptime-w33d probe %d", time.Now().UnixNano())
+	}
+
+	received := make(chan string, 16)
+	sub := client.Subscribe(monitor.Topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		select {
+		case received <- string(msg.Payload()):
+		default:
+		}
+	})
+	if !sub.WaitTimeout(timeout) {
+		return p.RecordResult(false, "subscribe timed out", time.Since(start))
+	}
+	if err := sub.Error(); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("subscribe failed: %v", err), time.Since(start))
+	}
+
+	if !retained {
+		pub := client.Publish(monitor.Topic, 1, false, probeMsg)
+		if !pub.WaitTimeout(timeout) {
+			return p.RecordResult(false, "publish timed out", time.Since(start))
+		}
+		if err := pub.Error(); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("publish failed: %v", err), time.Since(start))
+		}
+	}
+
+	deadline := time.After(timeout - time.Since(start))
+	for {
+		select {
+		case payload := <-received:
+			if retained {
+				res := p.RecordResult(payload == monitor.ExpectedValue, fmt.Sprintf("Retained value: %s", truncate(payload, 100)), time.Since(start))
+				if !res.Success {
+					res.Message = fmt.Sprintf("Value mismatch: expected '%s', got '%s'", monitor.ExpectedValue, truncate(payload, 100))
+				}
+				res.Data["value"] = truncate(payload, 1024)
+				return res
+			}
+			if payload == probeMsg {
+				return p.RecordResult(true, "Probe message received", time.Since(start))
+			}
+			// Someone else's traffic on the topic, keep waiting
+		case <-deadline:
+			if retained {
+				return p.RecordResult(false, "no retained message on topic", time.Since(start))
+			}
+			return p.RecordResult(false, "probe message not received within timeout", time.Since(start))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package probe_test
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+// startBroker runs an in-process broker with TCP and WebSocket listeners
+// that only accepts user "sensor" / "pw".
+func startBroker(t *testing.T) (*mochi.Server, string, string) {
+	t.Helper()
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	err := server.AddHook(new(auth.Hook), &auth.Options{
+		Ledger: &auth.Ledger{
+			Auth: auth.AuthRules{{Username: "sensor", Password: "pw", Allow: true}},
+			ACL:  auth.ACLRules{{Filters: auth.Filters{"#": auth.ReadWrite}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("auth hook: %v", err)
+	}
+
+	tcpAddr, wsAddr := freeAddr(t), freeAddr(t)
+	if err := server.AddListener(listeners.NewTCP(listeners.Config{ID: "tcp", Address: tcpAddr})); err != nil {
+		t.Fatalf("tcp listener: %v", err)
+	}
+	if err := server.AddListener(listeners.NewWebsocket(listeners.Config{ID: "ws", Address: wsAddr})); err != nil {
+		t.Fatalf("ws listener: %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	return server, "tcp://" + tcpAddr, "ws://" + wsAddr
+}
+
+func TestMQTTProbe_Check_RoundTrip(t *testing.T) {
+	_, tcpURL, wsURL := startBroker(t)
+
+	p := probe.NewMQTTProbe()
+	for _, target := range []string{tcpURL, wsURL} {
+		m := models.Monitor{
+			Type:     models.TypeMQTT,
+			Target:   target,
+			Timeout:  2,
+			Username: "sensor",
+			Password: "pw",
+			Topic:    "uptime/probe",
+		}
+		if result := p.Check(m); !result.Success {
+			t.Errorf("%s: expected round trip success, got failure: %s", target, result.Message)
+		}
+	}
+
+	m := models.Monitor{
+		Type:     models.TypeMQTT,
+		Target:   tcpURL,
+		Timeout:  1,
+		Username: "sensor",
+		Password: "wrong",
+	}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected auth failure, got success")
+	}
+}
+
+func TestMQTTProbe_Check_Retained(t *testing.T) {
+	server, tcpURL, _ := startBroker(t)
+	if err := server.Publish("site/firmware", []byte("1.4.2"), true, 0); err != nil {
+		t.Fatalf("publish retained: %v", err)
+	}
+
+	p := probe.NewMQTTProbe()
+	m := models.Monitor{
+		Type:          models.TypeMQTT,
+		Target:        tcpURL,
+		Timeout:       1,
+		Username:      "sensor",
+		Password:      "pw",
+		Topic:         "site/firmware",
+		ExpectedValue: "1.4.2",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected retained value match, got failure: %s", result.Message)
+	}
+
+	m.ExpectedValue = "1.5.0"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected retained value mismatch, got success")
+	}
+
+	m.Topic = "site/nothing-retained"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure with no retained message, got success")
+	}
+}
@@ -0,0 +1,115 @@
+package probe
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"uptime_w33d/internal/models"
+)
+
+type MySQLProbe struct {
+	BaseProbe
+}
+
+func NewMySQLProbe() *MySQLProbe {
+	return &MySQLProbe{}
+}
+
+func (p *MySQLProbe) Type() models.MonitorType {
+	return models.TypeMySQL
+}
+
+// Check connects to monitor.Target ("host:port", a mysql:// URL or a
+// go-sql-driver DSN like "user:pass@tcp(host:3306)/db") and runs Query (default "SELECT 1").
+func (p *MySQLProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	cfg, err := parseMySQLTarget(monitor.Target)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid DSN: %v", err), 0)
+	}
+	if monitor.Username != "" {
+		cfg.User = monitor.Username
+	}
+	if monitor.Password != "" {
+		cfg.Passwd = monitor.Password
+	}
+	if monitor.UseTLS {
+		cfg.TLSConfig = "true"
+		if !monitor.VerifyTLS {
+			cfg.TLSConfig = "skip-verify"
+		}
+	}
+	cfg.Timeout = timeout
+	cfg.ReadTimeout = timeout
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid DSN: %v", err), 0)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer conn.Close()
+
+	query := monitor.Query
+	if query == "" {
+		query = "SELECT 1"
+	}
+
+	var value interface{}
+	err = conn.QueryRowContext(ctx, query).Scan(&value)
+	duration := time.Since(start)
+	if err != nil && err != sql.ErrNoRows {
+		return p.RecordResult(false, fmt.Sprintf("query failed: %v", err), duration)
+	}
+
+	res := checkValue(value, err == sql.ErrNoRows, monitor.ExpectedValue, duration)
+
+	var version string
+	if conn.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version) == nil {
+		res.Data["server_version"] = version
+	}
+	return res
+}
+
+func parseMySQLTarget(target string) (*mysql.Config, error) {
+	if strings.HasPrefix(target, "mysql://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		cfg := mysql.NewConfig()
+		cfg.Net = "tcp"
+		cfg.Addr = u.Host
+		cfg.DBName = strings.TrimPrefix(u.Path, "/")
+		if u.User != nil {
+			cfg.User = u.User.Username()
+			cfg.Passwd, _ = u.User.Password()
+		}
+		return cfg, nil
+	}
+
+	if strings.Contains(target, "@") || strings.Contains(target, "(") {
+		return mysql.ParseDSN(target)
+	}
+
+	// Plain host:port
+	cfg := mysql.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = target
+	return cfg, nil
+}
@@ -0,0 +1,143 @@
+package probe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+const (
+	ntpPacketSize    = 48
+	defaultMaxOffset = 1000 // ms
+	ntpLeapNotInSync = 3
+)
+
+// ntpEpochOffset is the number of seconds between 1900 (NTP) and 1970 (Unix).
+const ntpEpochOffset = 2208988800
+
+type NTPProbe struct {
+	BaseProbe
+}
+
+func NewNTPProbe() *NTPProbe {
+	return &NTPProbe{}
+}
+
+func (p *NTPProbe) Type() models.MonitorType {
+	return models.TypeNTP
+}
+
+// Check sends a single SNTP client request and fails when the server reports
+// itself unsynchronised (leap indicator 3, stratum 16 or a kiss-o'-death) or our clock
+// differs from it by more than MaxOffset milliseconds.
+func (p *NTPProbe) Check(monitor models.Monitor) Result {
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	addr := withDefaultPort(monitor.Target, "123", "123", false)
+
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), 0)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	req := make([]byte, ntpPacketSize)
+	req[0] = 0<<6 | 4<<3 | 3 // LI 0, version 4, mode 3 (client)
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTPTime(t1)) // Transmit timestamp, echoed as origin
+
+	if _, err := conn.Write(req); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("send failed: %v", err), time.Since(t1))
+	}
+	resp := make([]byte, ntpPacketSize)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("no reply: %v", err), time.Since(t1))
+	}
+	if err := validateNTPResponse(resp[:n], req); err != nil {
+		return p.RecordResult(false, err.Error(), t4.Sub(t1))
+	}
+
+	leap := resp[0] >> 6
+	stratum := int(resp[1])
+	t2 := fromNTPTime(binary.BigEndian.Uint64(resp[32:])) // Receive timestamp
+	t3 := fromNTPTime(binary.BigEndian.Uint64(resp[40:])) // Transmit timestamp
+
+	offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+	delay := t4.Sub(t1) - t3.Sub(t2)
+
+	maxOffset := monitor.MaxOffset
+	if maxOffset <= 0 {
+		maxOffset = defaultMaxOffset
+	}
+
+	success := true
+	msg := fmt.Sprintf("Stratum %d, offset %v, delay %v", stratum, offset.Round(time.Microsecond), delay.Round(time.Microsecond))
+	switch {
+	case leap == ntpLeapNotInSync || stratum >= 16:
+		success = false
+		msg = fmt.Sprintf("Server is not synchronised (leap %d, stratum %d)", leap, stratum)
+	case math.Abs(float64(offset.Milliseconds())) > float64(maxOffset):
+		success = false
+		msg = fmt.Sprintf("Clock offset %v exceeds %dms", offset.Round(time.Millisecond), maxOffset)
+	}
+
+	res := p.RecordResult(success, msg, delay)
+	res.Data["stratum"] = stratum
+	res.Data["leap"] = int(leap)
+	res.Data["offset_ms"] = millis(offset)
+	res.Data["delay_ms"] = millis(delay)
+	res.Data["reference_id"] = ntpReferenceID(resp[12:16], stratum)
+	return res
+}
+
+func validateNTPResponse(resp, req []byte) error {
+	if len(resp) < ntpPacketSize {
+		return errors.New("short NTP response")
+	}
+	if mode := resp[0] & 0x07; mode != 4 {
+		return fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	// The origin timestamp must echo our transmit timestamp
+	if binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]) {
+		return errors.New("NTP response does not match the request")
+	}
+	if resp[1] == 0 {
+		// Kiss-o'-Death, the code is in the reference ID
+		return fmt.Errorf("kiss-o'-death from server: %s", string(resp[12:16]))
+	}
+	return nil
+}
+
+// ntpReferenceID is the upstream clock: a source name at stratum 1, an IPv4 address above.
+func ntpReferenceID(b []byte, stratum int) string {
+	if stratum <= 1 {
+		return string(trimNulls(b))
+	}
+	return net.IP(b).String()
+}
+
+func trimNulls(b []byte) []byte {
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+func toNTPTime(t time.Time) uint64 {
+	secs := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / 1e9
+	return secs<<32 | frac
+}
+
+func fromNTPTime(v uint64) time.Time {
+	secs := int64(v>>32) - ntpEpochOffset
+	nanos := int64((v & 0xffffffff) * 1e9 >> 32)
+	return time.Unix(secs, nanos)
+}
@@ -0,0 +1,89 @@
+package probe_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// startNTPServer answers SNTP requests with a clock skewed by skew.
+func startNTPServer(t *testing.T, skew time.Duration, leap, stratum byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ntpTime := func(t time.Time) uint64 {
+		secs := uint64(t.Unix() + 2208988800)
+		frac := uint64(t.Nanosecond()) << 32 / 1e9
+		return secs<<32 | frac
+	}
+
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 48 {
+				continue
+			}
+			recv := time.Now().Add(skew)
+			resp := make([]byte, 48)
+			resp[0] = leap<<6 | 4<<3 | 4 // server mode
+			resp[1] = stratum
+			copy(resp[12:16], "GPS\x00")
+			copy(resp[24:32], buf[40:48]) // origin = client transmit
+			binary.BigEndian.PutUint64(resp[32:], ntpTime(recv))
+			binary.BigEndian.PutUint64(resp[40:], ntpTime(time.Now().Add(skew)))
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestNTPProbe_Check(t *testing.T) {
+	p := probe.NewNTPProbe()
+
+	m := models.Monitor{Type: models.TypeNTP, Target: startNTPServer(t, 0, 0, 1), Timeout: 1}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["stratum"] != 1 || result.Data["reference_id"] != "GPS" {
+		t.Errorf("Unexpected data: %v", result.Data)
+	}
+	if offset, _ := result.Data["offset_ms"].(float64); offset < -50 || offset > 50 {
+		t.Errorf("Expected a near-zero offset, got %v", offset)
+	}
+
+	// Two seconds fast: fine with a generous threshold, down with the default
+	m.Target = startNTPServer(t, 2*time.Second, 0, 2)
+	result = p.Check(m)
+	if result.Success {
+		t.Errorf("Expected failure for a 2s offset, got success")
+	}
+	if offset, _ := result.Data["offset_ms"].(float64); offset < 1900 || offset > 2100 {
+		t.Errorf("Expected ~2000ms offset, got %v", offset)
+	}
+	m.MaxOffset = 5000
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success under a 5s threshold, got failure: %s", result.Message)
+	}
+
+	m = models.Monitor{Type: models.TypeNTP, Target: startNTPServer(t, 0, 3, 2), Timeout: 1}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for an unsynchronised server, got success")
+	}
+	m.Target = startNTPServer(t, 0, 0, 0)
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for a kiss-o'-death, got success")
+	}
+}
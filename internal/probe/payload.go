@@ -0,0 +1,76 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decodePayload converts the monitor's payload into bytes.
+// Format "hex" accepts hex digits with optional spaces ("FF FF FF FF 54"),
+// anything else sends the text as-is.
+func decodePayload(payload, format string) ([]byte, error) {
+	if format == "hex" {
+		b, err := hex.DecodeString(stripHexSpaces(payload))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex payload: %v", err)
+		}
+		return b, nil
+	}
+	return []byte(payload), nil
+}
+
+// responseMatcher checks received bytes against an expected response.
+// Mode is "contains" (default, literal substring), "regex" or "hex" (hex encoded prefix/substring).
+type responseMatcher struct {
+	literal []byte
+	re      *regexp.Regexp
+}
+
+func newResponseMatcher(expect, mode string) (*responseMatcher, error) {
+	switch mode {
+	case "", "contains", "text":
+		return &responseMatcher{literal: []byte(expect)}, nil
+	case "regex":
+		re, err := regexp.Compile(expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect regex: %v", err)
+		}
+		return &responseMatcher{re: re}, nil
+	case "hex":
+		b, err := hex.DecodeString(stripHexSpaces(expect))
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect hex: %v", err)
+		}
+		return &responseMatcher{literal: b}, nil
+	default:
+		return nil, fmt.Errorf("unknown expect mode: %s", mode)
+	}
+}
+
+func (m *responseMatcher) Match(data []byte) bool {
+	if m.re != nil {
+		return m.re.Match(data)
+	}
+	return bytes.Contains(data, m.literal)
+}
+
+func stripHexSpaces(s string) string {
+	return strings.NewReplacer(" ", "", "\n", "", "\t", "", ":", "").Replace(s)
+}
+
+// printable renders received bytes for messages: quoted text, or hex for binary data.
+func printable(data []byte, max int) string {
+	if len(data) > max {
+		data = data[:max]
+	}
+	for _, b := range data {
+		if (b < 0x20 && b != '\r' && b != '\n' && b != '\t') || b > 0x7e {
+			return hex.EncodeToString(data)
+		}
+	}
+	return strconv.Quote(string(data))
+}
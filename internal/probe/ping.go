@@ -1,13 +1,21 @@
 package probe
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"syscall"
 	"time"
 
 	probing "github.com/prometheus-community/pro-bing"
 	"uptime_w33d/internal/models"
 )
 
+const (
+	defaultPingCount    = 3
+	defaultPingInterval = time.Second
+)
+
 type PingProbe struct {
 	BaseProbe
 }
@@ -20,36 +28,93 @@ func (p *PingProbe) Type() models.MonitorType {
 	return models.TypePing
 }
 
+// Check sends PingCount echo requests and judges the run by packet loss and
+// jitter (RTT standard deviation). Raw ICMP sockets are tried first; without
+// CAP_NET_RAW it falls back to unprivileged UDP ICMP (net.ipv4.ping_group_range).
 func (p *PingProbe) Check(monitor models.Monitor) Result {
-	pinger, err := probing.NewPinger(monitor.Target)
+	stats, privileged, err := runPing(monitor, true)
+	if err != nil && isPermissionError(err) {
+		stats, privileged, err = runPing(monitor, false)
+	}
 	if err != nil {
-		return p.RecordResult(false, fmt.Sprintf("failed to init pinger: %v", err), 0)
+		return p.RecordResult(false, fmt.Sprintf("ping failed: %v", err), 0)
 	}
 
-	pinger.Count = 3
-	pinger.Timeout = time.Duration(monitor.Timeout) * time.Second
-	
-	// On Windows, privileged ping is usually required or specific OS settings.
-	// Try unprivileged first (UDP), if fails, might need SetPrivileged(true) and run as Admin.
-	// For better compatibility in this demo, we assume standard usage.
-	pinger.SetPrivileged(true) 
+	loss := stats.PacketLoss
+	jitter := millis(stats.StdDevRtt)
+
+	downLoss := monitor.DownLoss
+	if downLoss <= 0 {
+		downLoss = 100
+	}
+
+	success, degraded := true, false
+	msg := fmt.Sprintf("Avg RTT: %v, Jitter: %v, Loss: %.2f%%", stats.AvgRtt, stats.StdDevRtt, loss)
+	switch {
+	case stats.PacketsRecv == 0:
+		success = false
+		msg = "100% packet loss"
+	case loss >= downLoss:
+		success = false
+		msg = fmt.Sprintf("Packet loss %.2f%% (down at %.2f%%)", loss, downLoss)
+	case monitor.DownJitter > 0 && jitter >= float64(monitor.DownJitter):
+		success = false
+		msg = fmt.Sprintf("Jitter %v (down at %dms)", stats.StdDevRtt, monitor.DownJitter)
+	case monitor.DegradedLoss > 0 && loss >= monitor.DegradedLoss:
+		degraded = true
+		msg = fmt.Sprintf("Packet loss %.2f%% (degraded at %.2f%%)", loss, monitor.DegradedLoss)
+	case monitor.DegradedJitter > 0 && jitter >= float64(monitor.DegradedJitter):
+		degraded = true
+		msg = fmt.Sprintf("Jitter %v (degraded at %dms)", stats.StdDevRtt, monitor.DegradedJitter)
+	}
+
+	res := p.RecordResult(success, msg, stats.AvgRtt)
+	res.Degraded = degraded
+	res.Data["packets_sent"] = stats.PacketsSent
+	res.Data["packets_recv"] = stats.PacketsRecv
+	res.Data["packet_loss"] = loss
+	res.Data["min_rtt_ms"] = millis(stats.MinRtt)
+	res.Data["avg_rtt_ms"] = millis(stats.AvgRtt)
+	res.Data["max_rtt_ms"] = millis(stats.MaxRtt)
+	res.Data["jitter_ms"] = jitter
+	res.Data["privileged"] = privileged
+	return res
+}
 
-	err = pinger.Run() // Blocks until finished
+func runPing(monitor models.Monitor, privileged bool) (*probing.Statistics, bool, error) {
+	pinger, err := probing.NewPinger(monitor.Target)
 	if err != nil {
-		return p.RecordResult(false, fmt.Sprintf("ping failed: %v", err), 0)
+		return nil, privileged, err
+	}
+
+	pinger.Count = defaultPingCount
+	if monitor.PingCount > 0 {
+		pinger.Count = monitor.PingCount
+	}
+	pinger.Interval = defaultPingInterval
+	if monitor.PingInterval > 0 {
+		pinger.Interval = time.Duration(monitor.PingInterval) * time.Millisecond
 	}
+	if monitor.PacketSize > 0 {
+		pinger.Size = monitor.PacketSize
+	}
+
+	// Leave room for the whole series plus one timeout for the last reply
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	pinger.Timeout = time.Duration(pinger.Count-1)*pinger.Interval + timeout
+	pinger.SetPrivileged(privileged)
 
-	stats := pinger.Statistics()
-	duration := stats.AvgRtt
-	
-	if stats.PacketLoss > 0 {
-		// Consider it a failure if packet loss is 100%, or degraded?
-		// Simple logic: if 100% loss -> fail.
-		if stats.PacketLoss == 100 {
-			return p.RecordResult(false, "100% packet loss", 0)
-		}
+	if err := pinger.Run(); err != nil { // Blocks until finished
+		return nil, privileged, err
 	}
+	return pinger.Statistics(), privileged, nil
+}
+
+func isPermissionError(err error) bool {
+	return errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES)
+}
 
-	msg := fmt.Sprintf("Avg RTT: %v, Loss: %.2f%%", stats.AvgRtt, stats.PacketLoss)
-	return p.RecordResult(true, msg, duration)
+// millis converts a duration to fractional milliseconds.
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
 }
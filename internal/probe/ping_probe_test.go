@@ -0,0 +1,47 @@
+package probe_test
+
+import (
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func TestPingProbe_Check_Localhost(t *testing.T) {
+	p := probe.NewPingProbe()
+	m := models.Monitor{
+		Type:         models.TypePing,
+		Target:       "127.0.0.1",
+		Timeout:      1,
+		PingCount:    4,
+		PingInterval: 50,
+		PacketSize:   64,
+	}
+	result := p.Check(m)
+	if !result.Success && strings.Contains(result.Message, "ping failed") {
+		t.Skipf("ICMP not available in this environment: %s", result.Message)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["packets_sent"] != 4 {
+		t.Errorf("Expected 4 packets sent, got %v", result.Data["packets_sent"])
+	}
+	for _, key := range []string{"min_rtt_ms", "avg_rtt_ms", "max_rtt_ms", "jitter_ms", "packet_loss"} {
+		if _, ok := result.Data[key].(float64); !ok {
+			t.Errorf("Expected %s in data, got %v", key, result.Data[key])
+		}
+	}
+
+	m.DegradedJitter = 10000
+	m.DownJitter = 20000
+	if result := p.Check(m); !result.Success || result.Degraded {
+		t.Errorf("Expected plain success under generous thresholds, got %v (%s)", result.Success, result.Message)
+	}
+
+	m.PacketSize = 8
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for a packet size below the minimum, got success")
+	}
+}
@@ -0,0 +1,102 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"uptime_w33d/internal/models"
+)
+
+type PostgresProbe struct {
+	BaseProbe
+}
+
+func NewPostgresProbe() *PostgresProbe {
+	return &PostgresProbe{}
+}
+
+func (p *PostgresProbe) Type() models.MonitorType {
+	return models.TypePostgres
+}
+
+// Check connects to monitor.Target, either a "host:port" or a full
+// postgres:// URL / key=value DSN, and runs Query (default "SELECT 1").
+// UseTLS/VerifyTLS pick the sslmode, otherwise the DSN's or pgx's "prefer".
+func (p *PostgresProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	dsn := monitor.Target
+	if !strings.Contains(dsn, "://") && !strings.Contains(dsn, "=") {
+		dsn = "postgres://" + dsn
+	}
+	dsn, err := withSSLMode(dsn, monitor)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid DSN: %v", err), 0)
+	}
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid DSN: %v", err), 0)
+	}
+	if monitor.Username != "" {
+		cfg.User = monitor.Username
+	}
+	if monitor.Password != "" {
+		cfg.Password = monitor.Password
+	}
+	cfg.ConnectTimeout = timeout
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer conn.Close(context.Background())
+
+	query := monitor.Query
+	if query == "" {
+		query = "SELECT 1"
+	}
+
+	var value interface{}
+	err = conn.QueryRow(ctx, query).Scan(&value)
+	duration := time.Since(start)
+	if err != nil && err != pgx.ErrNoRows {
+		return p.RecordResult(false, fmt.Sprintf("query failed: %v", err), duration)
+	}
+
+	res := checkValue(value, err == pgx.ErrNoRows, monitor.ExpectedValue, duration)
+	res.Data["server_version"] = conn.PgConn().ParameterStatus("server_version")
+	return res
+}
+
+// withSSLMode sets sslmode on the DSN to "require" or "verify-full" with
+// UseTLS. Without it the DSN is left alone, opting out is sslmode=disable.
+func withSSLMode(dsn string, monitor models.Monitor) (string, error) {
+	if !monitor.UseTLS {
+		return dsn, nil
+	}
+	mode := "require"
+	if monitor.VerifyTLS {
+		mode = "verify-full"
+	}
+
+	if !strings.Contains(dsn, "://") {
+		// key=value DSN, later keys win
+		return dsn + " sslmode=" + mode, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("sslmode", mode)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
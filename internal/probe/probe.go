@@ -8,6 +8,7 @@ import (
 
 type Result struct {
 	Success      bool
+	Degraded     bool // Up, but past a soft threshold
 	ResponseTime time.Duration
 	Message      string
 	Data         map[string]interface{}
@@ -0,0 +1,418 @@
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+var aggregationPattern = regexp.MustCompile(`^(sum|min|max|avg|count)\s*\(`)
+
+type PrometheusProbe struct {
+	BaseProbe
+}
+
+func NewPrometheusProbe() *PrometheusProbe {
+	return &PrometheusProbe{}
+}
+
+func (p *PrometheusProbe) Type() models.MonitorType {
+	return models.TypePrometheus
+}
+
+// metricSample is one series of a scrape.
+type metricSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// labelMatcher is one label condition of a selector: =, !=, =~ or !~.
+type labelMatcher struct {
+	Name  string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+// metricExpr is a parsed expression like
+// `sum(queue_depth{queue=~"email|sms"}) < 1000`.
+type metricExpr struct {
+	Aggregation string
+	Name        string
+	Matchers    []labelMatcher
+	Op          string // Empty = just report the value
+	Threshold   string
+}
+
+// Check scrapes the Prometheus/OpenMetrics endpoint in monitor.Target and
+// evaluates monitor.Query. Without an aggregation the selector must match
+// exactly one series.
+func (p *PrometheusProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	expr, err := parseMetricExpr(monitor.Query)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid expression: %v", err), 0)
+	}
+
+	client, err := newHTTPClient(monitor, timeout)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+	req, err := http.NewRequest(http.MethodGet, monitor.Target, nil)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid request: %v", err), 0)
+	}
+	req.Header.Set("User-Agent", "UptimeW33d/1.0")
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	if monitor.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(monitor.Headers), &headers); err == nil {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("scrape failed: %v", err), time.Since(start))
+	}
+	defer resp.Body.Close()
+
+	body, truncated, err := readBody(resp.Body, monitor.MaxBodySize)
+	duration := time.Since(start)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("failed to read body: %v", err), duration)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return p.RecordResult(false, fmt.Sprintf("scrape returned HTTP %d", resp.StatusCode), duration)
+	}
+	if truncated {
+		return p.RecordResult(false, "scrape exceeds the maximum body size", duration)
+	}
+
+	samples, err := parseExposition(body)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid exposition format: %v", err), duration)
+	}
+
+	value, matched, err := expr.Evaluate(samples)
+	if err != nil {
+		res := p.RecordResult(false, err.Error(), duration)
+		res.Data["matched_series"] = matched
+		return res
+	}
+
+	actual := strconv.FormatFloat(value, 'f', -1, 64)
+	success := true
+	msg := fmt.Sprintf("%s = %s", monitor.Query, actual)
+	if expr.Op != "" {
+		ok, err := compare(expr.Op, actual, expr.Threshold, true)
+		if err != nil {
+			return p.RecordResult(false, err.Error(), duration)
+		}
+		if !ok {
+			success = false
+			msg = fmt.Sprintf("%s failed: value is %s", monitor.Query, actual)
+		} else {
+			msg = fmt.Sprintf("%s (value %s)", monitor.Query, actual)
+		}
+	}
+
+	res := p.RecordResult(success, msg, duration)
+	res.Data["value"] = value
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		// JSON has no NaN/Inf, the stored result would fail to serialize
+		res.Data["value"] = actual
+	}
+	res.Data["matched_series"] = matched
+	return res
+}
+
+// Evaluate selects the matching samples and reduces them to one value.
+func (e *metricExpr) Evaluate(samples []metricSample) (float64, int, error) {
+	var values []float64
+	for _, s := range samples {
+		if s.Name == e.Name && e.matches(s.Labels) {
+			values = append(values, s.Value)
+		}
+	}
+
+	if e.Aggregation == "count" {
+		return float64(len(values)), len(values), nil
+	}
+	if len(values) == 0 {
+		return 0, 0, fmt.Errorf("no series matches %s", e.selector())
+	}
+	if e.Aggregation == "" {
+		if len(values) > 1 {
+			return 0, len(values), fmt.Errorf("%d series match %s, narrow the labels or aggregate", len(values), e.selector())
+		}
+		return values[0], 1, nil
+	}
+
+	sum, min, max := 0.0, values[0], values[0]
+	for _, v := range values {
+		sum += v
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	switch e.Aggregation {
+	case "sum":
+		return sum, len(values), nil
+	case "avg":
+		return sum / float64(len(values)), len(values), nil
+	case "min":
+		return min, len(values), nil
+	}
+	return max, len(values), nil
+}
+
+func (e *metricExpr) matches(labels map[string]string) bool {
+	for _, m := range e.Matchers {
+		v := labels[m.Name]
+		switch m.Op {
+		case "=":
+			if v != m.Value {
+				return false
+			}
+		case "!=":
+			if v == m.Value {
+				return false
+			}
+		case "=~":
+			if !m.re.MatchString(v) {
+				return false
+			}
+		case "!~":
+			if m.re.MatchString(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *metricExpr) selector() string {
+	if len(e.Matchers) == 0 {
+		return e.Name
+	}
+	parts := make([]string, 0, len(e.Matchers))
+	for _, m := range e.Matchers {
+		parts = append(parts, fmt.Sprintf("%s%s%q", m.Name, m.Op, m.Value))
+	}
+	return fmt.Sprintf("%s{%s}", e.Name, strings.Join(parts, ","))
+}
+
+// parseMetricExpr parses `[agg(]name[{matchers}][)] [op number]`.
+func parseMetricExpr(s string) (*metricExpr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	expr := &metricExpr{}
+
+	if m := aggregationPattern.FindStringSubmatch(s); m != nil {
+		expr.Aggregation = m[1]
+		s = s[len(m[0]):]
+	}
+
+	rest, err := expr.parseSelector(s)
+	if err != nil {
+		return nil, err
+	}
+	rest = strings.TrimSpace(rest)
+	if expr.Aggregation != "" {
+		if !strings.HasPrefix(rest, ")") {
+			return nil, fmt.Errorf("missing ) after %s(", expr.Aggregation)
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+	if rest == "" {
+		return expr, nil
+	}
+
+	for _, op := range []string{"<=", ">=", "==", "!=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			expr.Op = op
+			expr.Threshold = strings.TrimSpace(rest[len(op):])
+			if _, err := strconv.ParseFloat(expr.Threshold, 64); err != nil {
+				return nil, fmt.Errorf("threshold must be a number, got '%s'", expr.Threshold)
+			}
+			return expr, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected '%s'", rest)
+}
+
+// parseSelector reads the metric name and label matchers and returns what follows.
+func (e *metricExpr) parseSelector(s string) (string, error) {
+	i := 0
+	for i < len(s) && isMetricChar(s[i], i == 0) {
+		i++
+	}
+	if i == 0 {
+		return "", fmt.Errorf("expected a metric name")
+	}
+	e.Name = s[:i]
+	s = strings.TrimSpace(s[i:])
+	if !strings.HasPrefix(s, "{") {
+		return s, nil
+	}
+
+	labels, rest, err := parseLabels(s[1:], true)
+	if err != nil {
+		return "", err
+	}
+	for _, l := range labels {
+		m := labelMatcher{Name: l.name, Op: l.op, Value: l.value}
+		if m.Op == "=~" || m.Op == "!~" {
+			re, err := regexp.Compile("^(?:" + m.Value + ")$")
+			if err != nil {
+				return "", fmt.Errorf("invalid regex for label %s: %v", m.Name, err)
+			}
+			m.re = re
+		}
+		e.Matchers = append(e.Matchers, m)
+	}
+	return rest, nil
+}
+
+type rawLabel struct {
+	name, op, value string
+}
+
+// parseLabels reads `name="value", ...}` (after the opening brace). With
+// matchers it also accepts !=, =~ and !~.
+func parseLabels(s string, matchers bool) ([]rawLabel, string, error) {
+	var labels []rawLabel
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		i := 0
+		for i < len(s) && isMetricChar(s[i], i == 0) && s[i] != ':' {
+			i++
+		}
+		if i == 0 {
+			return nil, "", fmt.Errorf("expected a label name")
+		}
+		l := rawLabel{name: s[:i]}
+		s = strings.TrimSpace(s[i:])
+
+		l.op = "="
+		if matchers {
+			for _, op := range []string{"=~", "!~", "!=", "="} {
+				if strings.HasPrefix(s, op) {
+					l.op = op
+					break
+				}
+			}
+		}
+		if !strings.HasPrefix(s, l.op) {
+			return nil, "", fmt.Errorf("expected %s after label %s", l.op, l.name)
+		}
+		s = strings.TrimSpace(s[len(l.op):])
+
+		value, n, err := readQuoted(s)
+		if err != nil {
+			return nil, "", fmt.Errorf("label %s: %v", l.name, err)
+		}
+		l.value = value
+		s = s[n:]
+		labels = append(labels, l)
+	}
+}
+
+// readQuoted reads a double quoted string with \\, \" and \n escapes.
+func readQuoted(s string) (string, int, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", 0, fmt.Errorf("expected a quoted value")
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("unterminated escape")
+			}
+			i++
+			if s[i] == 'n' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted value")
+}
+
+func isMetricChar(c byte, first bool) bool {
+	return c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// parseExposition reads the Prometheus text format (and OpenMetrics, which is
+// the same for our purposes). Timestamps and exemplars are ignored.
+func parseExposition(body []byte) ([]metricSample, error) {
+	var samples []metricSample
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), len(body)+1)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := 0
+		for i < len(line) && isMetricChar(line[i], i == 0) {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("line %d: expected a metric name", lineNo)
+		}
+		sample := metricSample{Name: line[:i], Labels: map[string]string{}}
+		rest := line[i:]
+
+		if strings.HasPrefix(rest, "{") {
+			labels, after, err := parseLabels(rest[1:], false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			for _, l := range labels {
+				sample.Labels[l.name] = l.value
+			}
+			rest = after
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("line %d: missing value", lineNo)
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid value '%s'", lineNo, fields[0])
+		}
+		sample.Value = v
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
@@ -0,0 +1,96 @@
+package probe_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+const metricsPage = `# HELP queue_depth Messages waiting per queue.
+# TYPE queue_depth gauge
+queue_depth{queue="email",region="eu"} 420
+queue_depth{queue="sms",region="eu"} 1500 1700000000000
+queue_depth{queue="push",region="us",note="a \"quoted\" label"} 80
+# TYPE orders_total counter
+orders_total 12345
+cache_hit_ratio NaN
+request_seconds_max +Inf
+up 1
+# EOF
+`
+
+func TestPrometheusProbe_Check(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer scrape" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(metricsPage))
+	}))
+	defer ts.Close()
+
+	p := probe.NewPrometheusProbe()
+	check := func(query string) probe.Result {
+		return p.Check(models.Monitor{
+			Type:    models.TypePrometheus,
+			Target:  ts.URL,
+			Timeout: 1,
+			Headers: `{"Authorization":"Bearer scrape"}`,
+			Query:   query,
+		})
+	}
+
+	passing := map[string]float64{
+		`queue_depth{queue="email"} < 1000`:            420,
+		`orders_total`:                                 12345,
+		`sum(queue_depth{region="eu"}) == 1920`:        1920,
+		`max(queue_depth{queue=~"email|push"}) > 100`:  420,
+		`count(queue_depth{queue!="sms"}) >= 2`:        2,
+		`queue_depth{note="a \"quoted\" label"} <= 80`: 80,
+		`count(missing_metric) == 0`:                   0,
+	}
+	for query, want := range passing {
+		result := check(query)
+		if !result.Success {
+			t.Errorf("%s: expected success, got failure: %s", query, result.Message)
+			continue
+		}
+		if result.Data["value"] != want {
+			t.Errorf("%s: expected value %v, got %v", query, want, result.Data["value"])
+		}
+	}
+
+	failing := []string{
+		`queue_depth{queue="sms"} < 1000`, // threshold breached
+		`queue_depth{region="eu"} < 1000`, // ambiguous, two series
+		`missing_metric > 0`,              // no series
+		`queue_depth{queue="email"} < lots`,
+		`avg(queue_depth`,
+	}
+	for _, query := range failing {
+		if result := check(query); result.Success {
+			t.Errorf("%s: expected failure, got success", query)
+		}
+	}
+
+	// JSON has no NaN/Inf, those are kept as strings
+	for query, want := range map[string]string{"cache_hit_ratio": "NaN", "request_seconds_max": "+Inf"} {
+		result := check(query)
+		if result.Data["value"] != want {
+			t.Errorf("%s: expected value %q, got %v", query, want, result.Data["value"])
+		}
+		if _, err := json.Marshal(result.Data); err != nil {
+			t.Errorf("%s: result data does not serialize: %v", query, err)
+		}
+	}
+
+	m := models.Monitor{Type: models.TypePrometheus, Target: ts.URL, Timeout: 1, Query: "up == 1"}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure without credentials, got success")
+	}
+}
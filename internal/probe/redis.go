@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"uptime_w33d/internal/models"
+)
+
+type RedisProbe struct {
+	BaseProbe
+}
+
+func NewRedisProbe() *RedisProbe {
+	return &RedisProbe{}
+}
+
+func (p *RedisProbe) Type() models.MonitorType {
+	return models.TypeRedis
+}
+
+// Check connects to monitor.Target ("host:port" or a redis:// / rediss:// URL)
+// and runs Query as a command (default "PING").
+func (p *RedisProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	var opts *redis.Options
+	if strings.Contains(monitor.Target, "://") {
+		var err error
+		if opts, err = redis.ParseURL(monitor.Target); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid URL: %v", err), 0)
+		}
+	} else {
+		opts = &redis.Options{Addr: monitor.Target}
+	}
+	if monitor.Username != "" {
+		opts.Username = monitor.Username
+	}
+	if monitor.Password != "" {
+		opts.Password = monitor.Password
+	}
+	if monitor.UseTLS && opts.TLSConfig == nil {
+		opts.TLSConfig = &tls.Config{InsecureSkipVerify: !monitor.VerifyTLS}
+	}
+	opts.DialTimeout = timeout
+	opts.ReadTimeout = timeout
+	opts.WriteTimeout = timeout
+	opts.MaxRetries = -1 // The scheduler already retries
+	opts.PoolSize = 1
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	command := monitor.Query
+	if command == "" {
+		command = "PING"
+	}
+	fields := strings.Fields(command)
+	args := make([]interface{}, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+
+	value, err := client.Do(ctx, args...).Result()
+	duration := time.Since(start)
+	noRows := err == redis.Nil
+	if err != nil && !noRows {
+		return p.RecordResult(false, fmt.Sprintf("command failed: %v", err), duration)
+	}
+
+	res := checkValue(value, noRows, monitor.ExpectedValue, duration)
+	if info, err := client.Info(ctx, "server").Result(); err == nil {
+		if version := infoField(info, "redis_version"); version != "" {
+			res.Data["server_version"] = version
+		}
+	}
+	return res
+}
+
+// infoField extracts "key:value" from INFO output.
+func infoField(info, key string) string {
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		if k, v, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":"); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package probe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"uptime_w33d/internal/models"
+)
+
+type SSHProbe struct {
+	BaseProbe
+}
+
+func NewSSHProbe() *SSHProbe {
+	return &SSHProbe{}
+}
+
+func (p *SSHProbe) Type() models.MonitorType {
+	return models.TypeSSH
+}
+
+// Check completes the SSH handshake and compares the host key with
+// HostKeyFingerprint (SHA256:...). An empty pin is filled in by the scheduler
+// from the first key seen. With credentials (Password or PrivateKey) it logs
+// in and, if Query is set, runs it as a command and checks the exit code
+// (ExpectedStatus, default "0") and output (ExpectResponse).
+func (p *SSHProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	addr := withDefaultPort(monitor.Target, "22", "22", false)
+
+	var auth []ssh.AuthMethod
+	if monitor.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if monitor.Password != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(monitor.PrivateKey), []byte(monitor.Password))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(monitor.PrivateKey))
+		}
+		if err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid private key: %v", err), 0)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if monitor.Password != "" {
+		auth = append(auth, ssh.Password(monitor.Password))
+	}
+
+	user := monitor.Username
+	if user == "" {
+		user = "uptime-w33d"
+	}
+
+	var fingerprint, keyType string
+	config := &ssh.ClientConfig{
+		User:    user,
+		Auth:    auth,
+		Timeout: timeout,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintSHA256(key)
+			keyType = key.Type()
+			if monitor.HostKeyFingerprint != "" && fingerprint != monitor.HostKeyFingerprint {
+				return errHostKeyMismatch
+			}
+			return nil
+		},
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("connection failed: %v", err), time.Since(start))
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	duration := time.Since(start)
+
+	record := func(res Result) Result {
+		if fingerprint != "" {
+			res.Data["host_key_fingerprint"] = fingerprint
+			res.Data["host_key_type"] = keyType
+		}
+		return res
+	}
+
+	if err != nil {
+		if errors.Is(err, errHostKeyMismatch) {
+			return record(p.RecordResult(false, fmt.Sprintf("Host key changed: expected %s, got %s", monitor.HostKeyFingerprint, fingerprint), duration))
+		}
+		// Without credentials the handshake is all we wanted
+		if len(auth) == 0 && fingerprint != "" {
+			return record(p.RecordResult(true, "Handshake OK", duration))
+		}
+		return record(p.RecordResult(false, fmt.Sprintf("SSH failed: %v", err), duration))
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	res := record(p.RecordResult(true, "Login OK", duration))
+	res.Data["server_version"] = string(sshConn.ServerVersion())
+	if monitor.Query == "" {
+		return res
+	}
+
+	// Run Command
+	session, err := client.NewSession()
+	if err != nil {
+		return record(p.RecordResult(false, fmt.Sprintf("failed to open session: %v", err), time.Since(start)))
+	}
+	defer session.Close()
+
+	// Separate buffers: the session copies stdout and stderr from
+	// different goroutines.
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	exitCode := 0
+	if err := session.Run(monitor.Query); err != nil {
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			return record(p.RecordResult(false, fmt.Sprintf("command failed: %v", err), time.Since(start)))
+		}
+		exitCode = exitErr.ExitStatus()
+	}
+	duration = time.Since(start)
+	combined := append(stdout.Bytes(), stderr.Bytes()...)
+	output := strings.TrimSpace(string(combined))
+
+	expected := monitor.ExpectedStatus
+	if expected == "" {
+		expected = "0"
+	}
+	ok, err := StatusMatches(exitCode, expected)
+	if err != nil {
+		return record(p.RecordResult(false, fmt.Sprintf("invalid expected exit code: %v", err), duration))
+	}
+
+	msg := fmt.Sprintf("Exit %d: %s", exitCode, truncate(output, 100))
+	if !ok {
+		msg = fmt.Sprintf("Unexpected exit code: %d (expected %s): %s", exitCode, expected, truncate(output, 100))
+	} else if monitor.ExpectResponse != "" {
+		matcher, err := newResponseMatcher(monitor.ExpectResponse, monitor.ExpectMode)
+		if err != nil {
+			return record(p.RecordResult(false, err.Error(), duration))
+		}
+		if !matcher.Match(combined) {
+			ok = false
+			msg = fmt.Sprintf("unexpected output: %s", printable([]byte(output), 100))
+		}
+	}
+
+	res = record(p.RecordResult(ok, msg, duration))
+	res.Data["server_version"] = string(sshConn.ServerVersion())
+	res.Data["exit_code"] = exitCode
+	res.Data["output"] = truncate(output, 1024)
+	return res
+}
+
+var errHostKeyMismatch = errors.New("host key mismatch")
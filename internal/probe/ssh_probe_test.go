@@ -0,0 +1,156 @@
+package probe_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// startSSHServer accepts password "s3cret" and answers "exec" requests:
+// "uptime" prints a line and exits 0, anything else exits 3.
+func startSSHServer(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("keygen: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "monitor" && string(pass) == "s3cret" {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+	return ln.Addr().String(), signer.PublicKey()
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		ch, requests, err := newChan.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer ch.Close()
+			for req := range requests {
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				req.Reply(true, nil)
+				cmd := string(req.Payload[4:])
+				status := uint32(3)
+				if cmd == "uptime" {
+					ch.Write([]byte(" 10:00:00 up 42 days, load average: 0.01\n"))
+					status = 0
+				}
+				payload := make([]byte, 4)
+				binary.BigEndian.PutUint32(payload, status)
+				ch.SendRequest("exit-status", false, payload)
+				return
+			}
+		}()
+	}
+}
+
+func TestSSHProbe_Check_HostKeyPinning(t *testing.T) {
+	addr, hostKey := startSSHServer(t)
+	fingerprint := ssh.FingerprintSHA256(hostKey)
+
+	p := probe.NewSSHProbe()
+	m := models.Monitor{Type: models.TypeSSH, Target: addr, Timeout: 1}
+
+	// No pin yet: handshake succeeds and reports the key to pin
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected handshake success, got failure: %s", result.Message)
+	}
+	if result.Data["host_key_fingerprint"] != fingerprint {
+		t.Errorf("Expected fingerprint %s, got %v", fingerprint, result.Data["host_key_fingerprint"])
+	}
+
+	m.HostKeyFingerprint = fingerprint
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with matching pin, got failure: %s", result.Message)
+	}
+
+	m.HostKeyFingerprint = "SHA256:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for changed host key, got success")
+	}
+}
+
+func TestSSHProbe_Check_Command(t *testing.T) {
+	addr, _ := startSSHServer(t)
+
+	p := probe.NewSSHProbe()
+	m := models.Monitor{
+		Type:           models.TypeSSH,
+		Target:         addr,
+		Timeout:        1,
+		Username:       "monitor",
+		Password:       "s3cret",
+		Query:          "uptime",
+		ExpectResponse: "load average",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["exit_code"] != 0 {
+		t.Errorf("Expected exit code 0, got %v", result.Data["exit_code"])
+	}
+
+	m.Query = "false"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for exit code 3, got success")
+	}
+
+	m.ExpectedStatus = "0,3"
+	m.ExpectResponse = ""
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success when exit code 3 is allowed, got failure: %s", result.Message)
+	}
+
+	m.Password = "wrong"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected authentication failure, got success")
+	}
+}
@@ -2,6 +2,8 @@ package probe
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/rumblefrog/go-a2s"
@@ -20,6 +22,9 @@ func (p *SteamProbe) Type() models.MonitorType {
 	return models.TypeSteam
 }
 
+// Check runs A2S_INFO against the server and asserts on players, map, server
+// name and the password/VAC flags. QueryPlayers/QueryRules add the player list
+// and server rules to the result.
 func (p *SteamProbe) Check(monitor models.Monitor) Result {
 	start := time.Now()
 	timeout := time.Duration(monitor.Timeout) * time.Second
@@ -38,13 +43,115 @@ func (p *SteamProbe) Check(monitor models.Monitor) Result {
 	}
 
 	msg := fmt.Sprintf("%s (%d/%d players)", info.Name, info.Players, info.MaxPlayers)
-	
-	res := p.RecordResult(true, msg, duration)
+	success, degraded := true, false
+
+	switch {
+	case monitor.ExpectedMap != "" && !strings.EqualFold(info.Map, monitor.ExpectedMap):
+		success = false
+		msg = fmt.Sprintf("Map mismatch: expected '%s', got '%s'", monitor.ExpectedMap, info.Map)
+	case monitor.ExpectPassword != nil && info.Visibility != *monitor.ExpectPassword:
+		success = false
+		msg = fmt.Sprintf("Password protection is %s", onOff(info.Visibility))
+	case monitor.ExpectVAC != nil && info.VAC != *monitor.ExpectVAC:
+		success = false
+		msg = fmt.Sprintf("VAC is %s", onOff(info.VAC))
+	}
+	if success && monitor.ServerNamePattern != "" {
+		re, err := regexp.Compile(monitor.ServerNamePattern)
+		if err != nil {
+			return p.RecordResult(false, fmt.Sprintf("invalid server name pattern: %v", err), duration)
+		}
+		if !re.MatchString(info.Name) {
+			success = false
+			msg = fmt.Sprintf("Server name '%s' does not match '%s'", info.Name, monitor.ServerNamePattern)
+		}
+	}
+
+	population := populationState(int(info.Players), int(info.MaxPlayers))
+	if success {
+		success, degraded, msg = checkPlayers(monitor, int(info.Players), int(info.MaxPlayers), population, msg)
+	}
+
+	res := p.RecordResult(success, msg, duration)
+	res.Degraded = degraded
 	res.Data["server_name"] = info.Name
 	res.Data["map"] = info.Map
 	res.Data["players"] = info.Players
 	res.Data["max_players"] = info.MaxPlayers
+	res.Data["bots"] = info.Bots
 	res.Data["game"] = info.Game
+	res.Data["version"] = info.Version
+	res.Data["password"] = info.Visibility
+	res.Data["vac"] = info.VAC
+	res.Data["population"] = population
+
+	// Optional extra queries, a failure here does not fail the check
+	if monitor.QueryPlayers {
+		if players, err := client.QueryPlayer(); err == nil {
+			list := make([]map[string]interface{}, 0, len(players.Players))
+			for _, pl := range players.Players {
+				list = append(list, map[string]interface{}{
+					"name":     pl.Name,
+					"score":    pl.Score,
+					"duration": int(pl.Duration),
+				})
+			}
+			res.Data["player_list"] = list
+		} else {
+			res.Data["player_list_error"] = err.Error()
+		}
+	}
+	if monitor.QueryRules {
+		if rules, err := client.QueryRules(); err == nil {
+			res.Data["rules"] = rules.Rules
+		} else {
+			res.Data["rules_error"] = err.Error()
+		}
+	}
 
 	return res
 }
+
+// populationState classifies a game server as "full", "empty" or "" (neither).
+func populationState(online, capacity int) string {
+	switch {
+	case capacity > 0 && online >= capacity:
+		return "full"
+	case online == 0:
+		return "empty"
+	}
+	return ""
+}
+
+// checkPlayers applies MinPlayers/MaxPlayers and the full/empty-for-too-long
+// alerts. How long the server has been in its current state comes from
+// PopulationState/PopulationSince, kept by the scheduler.
+func checkPlayers(monitor models.Monitor, online, capacity int, population, msg string) (bool, bool, string) {
+	if monitor.MinPlayers > 0 && online < monitor.MinPlayers {
+		return false, false, fmt.Sprintf("Too few players: %d (min %d)", online, monitor.MinPlayers)
+	}
+	if monitor.MaxPlayers > 0 && online > monitor.MaxPlayers {
+		return false, false, fmt.Sprintf("Too many players: %d (max %d)", online, monitor.MaxPlayers)
+	}
+
+	limit := 0
+	switch population {
+	case "full":
+		limit = monitor.FullAlertAfter
+	case "empty":
+		limit = monitor.EmptyAlertAfter
+	}
+	if limit > 0 && population == monitor.PopulationState && monitor.PopulationSince != nil {
+		if since := time.Since(*monitor.PopulationSince); since >= time.Duration(limit)*time.Minute {
+			return true, true, fmt.Sprintf("Server %s for %s (%d/%d players)", population, since.Round(time.Minute), online, capacity)
+		}
+	}
+	return true, false, msg
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,142 @@
+package probe_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// startA2SServer answers A2S_INFO directly and A2S_PLAYER/A2S_RULES after a
+// challenge round trip, like a Source dedicated server.
+func startA2SServer(t *testing.T, players, maxPlayers byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	challenge := []byte{0x11, 0x22, 0x33, 0x44}
+	header := []byte{0xFF, 0xFF, 0xFF, 0xFF}
+
+	go func() {
+		buf := make([]byte, 1400)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 5 {
+				continue
+			}
+			var out bytes.Buffer
+			out.Write(header)
+			switch req := buf[4]; {
+			case req == 'T':
+				out.WriteByte('I')
+				out.WriteByte(17) // protocol
+				out.WriteString("[EU] Friendly Server\x00de_dust2\x00csgo\x00Counter-Strike\x00")
+				binary.Write(&out, binary.LittleEndian, uint16(730))
+				out.Write([]byte{players, maxPlayers, 0, 'd', 'l', 0, 1})
+				out.WriteString("1.38.7.9\x00")
+			case (req == 'U' || req == 'V') && !bytes.Equal(buf[5:9], challenge):
+				out.WriteByte('A')
+				out.Write(challenge)
+			case req == 'U':
+				out.WriteByte('D')
+				out.WriteByte(1)
+				out.WriteByte(0)
+				out.WriteString("gabe\x00")
+				binary.Write(&out, binary.LittleEndian, uint32(42))
+				binary.Write(&out, binary.LittleEndian, math.Float32bits(125.5))
+			case req == 'V':
+				out.WriteByte('E')
+				binary.Write(&out, binary.LittleEndian, uint16(1))
+				out.WriteString("mp_timelimit\x0030\x00")
+			default:
+				continue
+			}
+			conn.WriteTo(out.Bytes(), addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestSteamProbe_Check_Assertions(t *testing.T) {
+	addr := startA2SServer(t, 5, 24)
+	p := probe.NewSteamProbe()
+	yes, no := true, false
+
+	m := models.Monitor{
+		Type:              models.TypeSteam,
+		Target:            addr,
+		Timeout:           1,
+		MinPlayers:        2,
+		MaxPlayers:        20,
+		ExpectedMap:       "de_dust2",
+		ServerNamePattern: `^\[EU\]`,
+		ExpectPassword:    &no,
+		ExpectVAC:         &yes,
+		QueryPlayers:      true,
+		QueryRules:        true,
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if list, ok := result.Data["player_list"].([]map[string]interface{}); !ok || len(list) != 1 || list[0]["name"] != "gabe" {
+		t.Errorf("Expected player list, got %v", result.Data["player_list"])
+	}
+	if rules, ok := result.Data["rules"].(map[string]string); !ok || rules["mp_timelimit"] != "30" {
+		t.Errorf("Expected rules, got %v", result.Data["rules"])
+	}
+
+	failures := map[string]func(m *models.Monitor){
+		"min players": func(m *models.Monitor) { m.MinPlayers = 10 },
+		"max players": func(m *models.Monitor) { m.MaxPlayers = 4 },
+		"map":         func(m *models.Monitor) { m.ExpectedMap = "de_inferno" },
+		"name":        func(m *models.Monitor) { m.ServerNamePattern = `^\[US\]` },
+		"password":    func(m *models.Monitor) { m.ExpectPassword = &yes },
+		"vac":         func(m *models.Monitor) { m.ExpectVAC = &no },
+	}
+	for name, mutate := range failures {
+		mm := m
+		mm.QueryPlayers, mm.QueryRules = false, false
+		mutate(&mm)
+		if result := p.Check(mm); result.Success {
+			t.Errorf("%s: expected failure, got success", name)
+		}
+	}
+}
+
+func TestSteamProbe_Check_FullForTooLong(t *testing.T) {
+	addr := startA2SServer(t, 24, 24)
+	p := probe.NewSteamProbe()
+
+	m := models.Monitor{Type: models.TypeSteam, Target: addr, Timeout: 1, FullAlertAfter: 30}
+	result := p.Check(m)
+	if !result.Success || result.Degraded {
+		t.Fatalf("Expected plain success on first sight, got %v/%v: %s", result.Success, result.Degraded, result.Message)
+	}
+	if result.Data["population"] != "full" {
+		t.Errorf("Expected population full, got %v", result.Data["population"])
+	}
+
+	since := time.Now().Add(-45 * time.Minute)
+	m.PopulationState = "full"
+	m.PopulationSince = &since
+	if result := p.Check(m); !result.Degraded {
+		t.Errorf("Expected degraded after 45m full, got: %s", result.Message)
+	}
+
+	since = time.Now().Add(-10 * time.Minute)
+	if result := p.Check(m); result.Degraded {
+		t.Errorf("Expected no alert after 10m full, got: %s", result.Message)
+	}
+}
@@ -1,13 +1,18 @@
 package probe
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
 	"uptime_w33d/internal/models"
 )
 
+// maxTCPResponse caps how much of the response we buffer while waiting for a match.
+const maxTCPResponse = 64 * 1024
+
 type TCPProbe struct {
 	BaseProbe
 }
@@ -32,5 +37,67 @@ func (p *TCPProbe) Check(monitor models.Monitor) Result {
 	}
 	defer conn.Close()
 
-	return p.RecordResult(true, "Connection established", duration)
+	// Plain port check
+	if monitor.SendPayload == "" && monitor.ExpectResponse == "" {
+		return p.RecordResult(true, "Connection established", duration)
+	}
+
+	// Send/Expect check, e.g. Redis PING -> +PONG, or just read an SSH banner
+	payload, err := decodePayload(monitor.SendPayload, monitor.PayloadFormat)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), duration)
+	}
+	var matcher *responseMatcher
+	if monitor.ExpectResponse != "" {
+		if matcher, err = newResponseMatcher(monitor.ExpectResponse, monitor.ExpectMode); err != nil {
+			return p.RecordResult(false, err.Error(), duration)
+		}
+	}
+
+	readTimeout := timeout
+	if monitor.ReadTimeout > 0 {
+		readTimeout = time.Duration(monitor.ReadTimeout) * time.Second
+	}
+	conn.SetDeadline(time.Now().Add(readTimeout))
+
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("send failed: %v", err), time.Since(start))
+		}
+	}
+
+	if matcher == nil {
+		return p.RecordResult(true, fmt.Sprintf("Sent %d bytes", len(payload)), time.Since(start))
+	}
+
+	// Read until the expectation matches, the peer closes or the read timeout hits
+	var received []byte
+	buf := make([]byte, 4096)
+	for len(received) < maxTCPResponse {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if matcher.Match(received) {
+			res := p.RecordResult(true, fmt.Sprintf("Response matched: %s", printable(received, 100)), time.Since(start))
+			res.Data["response"] = printable(received, 1024)
+			return res
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return p.RecordResult(false, fmt.Sprintf("read failed: %v", err), time.Since(start))
+		}
+	}
+
+	msg := "no response received"
+	if len(received) > 0 {
+		msg = fmt.Sprintf("unexpected response: %s", printable(received, 100))
+	}
+	res := p.RecordResult(false, msg, time.Since(start))
+	res.Data["response"] = printable(received, 1024)
+	return res
 }
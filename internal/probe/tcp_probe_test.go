@@ -0,0 +1,102 @@
+package probe_test
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+// startLineServer answers each line with reply(line), after sending an optional banner.
+func startLineServer(t *testing.T, banner string, reply func(string) string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				if banner != "" {
+					c.Write([]byte(banner))
+				}
+				r := bufio.NewReader(c)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					c.Write([]byte(reply(strings.TrimSpace(line))))
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestTCPProbe_Check_SendExpect(t *testing.T) {
+	addr := startLineServer(t, "", func(line string) string {
+		if line == "PING" {
+			return "+PONG\r\n"
+		}
+		return "-ERR unknown command\r\n"
+	})
+
+	p := probe.NewTCPProbe()
+	m := models.Monitor{
+		Type:           models.TypeTCP,
+		Target:         addr,
+		Timeout:        1,
+		SendPayload:    "PING\r\n",
+		ExpectResponse: "+PONG",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success, got failure: %s", result.Message)
+	}
+
+	m.SendPayload = "50494e470d0a" // "PING\r\n"
+	m.PayloadFormat = "hex"
+	m.ExpectResponse = `^\+PONG`
+	m.ExpectMode = "regex"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with hex payload, got failure: %s", result.Message)
+	}
+
+	m.SendPayload = "INFO\r\n"
+	m.PayloadFormat = ""
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for unexpected reply, got success")
+	}
+}
+
+func TestTCPProbe_Check_Banner(t *testing.T) {
+	addr := startLineServer(t, "SSH-2.0-OpenSSH_9.6\r\n", func(string) string { return "" })
+
+	p := probe.NewTCPProbe()
+	m := models.Monitor{
+		Type:           models.TypeTCP,
+		Target:         addr,
+		Timeout:        1,
+		ExpectResponse: `^SSH-2\.0-`,
+		ExpectMode:     "regex",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected banner match, got failure: %s", result.Message)
+	}
+
+	m.ExpectResponse = "SMTP"
+	m.ExpectMode = ""
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for wrong banner, got success")
+	}
+}
@@ -0,0 +1,254 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	defaultMaxHops     = 30
+	defaultHopTimeout  = time.Second
+	traceroutePortBase = 33434 // Classic traceroute UDP port range
+)
+
+// TraceHop is one line of a traceroute. Addr is "*" when the hop stayed silent.
+type TraceHop struct {
+	TTL     int     `json:"ttl"`
+	Addr    string  `json:"addr"`
+	RTT     float64 `json:"rtt_ms,omitempty"`
+	Reached bool    `json:"reached,omitempty"`
+	// A router reported the destination unreachable, the trace ends here
+	Unreachable bool `json:"unreachable,omitempty"`
+}
+
+// Traceroute walks the path to host with increasing TTLs using "icmp" echo,
+// "udp" (high ports) or "tcp" SYN probes to port. It needs a raw ICMP socket
+// to hear the routers and only supports IPv4.
+func Traceroute(host string, port int, method string, maxHops int) ([]TraceHop, error) {
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
+	}
+	addr, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, fmt.Errorf("raw ICMP socket unavailable: %w", err)
+	}
+	defer conn.Close()
+
+	// Every trace shares the raw socket traffic of the process, a random
+	// echo ID keeps concurrent traces from reading each other's replies
+	id := rand.Intn(0xffff) + 1
+
+	var hops []TraceHop
+	for ttl := 1; ttl <= maxHops; ttl++ {
+		var hop TraceHop
+		switch method {
+		case "udp":
+			hop, err = traceUDP(conn, addr.IP, ttl)
+		case "tcp":
+			if port == 0 {
+				port = 80
+			}
+			hop, err = traceTCP(conn, addr.IP, port, ttl)
+		case "", "icmp":
+			hop, err = traceICMP(conn, addr.IP, id, ttl)
+		default:
+			return nil, fmt.Errorf("unknown traceroute method: %s", method)
+		}
+		if err != nil {
+			return hops, err
+		}
+		hops = append(hops, hop)
+		if hop.Reached || hop.Unreachable {
+			break
+		}
+	}
+	return hops, nil
+}
+
+// FormatHops renders hops one per line, traceroute style.
+func FormatHops(hops []TraceHop) string {
+	var b strings.Builder
+	for _, h := range hops {
+		if h.Addr == "*" {
+			fmt.Fprintf(&b, "%2d  *\n", h.TTL)
+			continue
+		}
+		fmt.Fprintf(&b, "%2d  %s  %.2fms", h.TTL, h.Addr, h.RTT)
+		if h.Unreachable {
+			b.WriteString("  !unreachable")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func traceICMP(conn *icmp.PacketConn, dst net.IP, id, ttl int) (TraceHop, error) {
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: id, Seq: ttl, Data: []byte("uptime-w33d")},
+	}
+	wb, err := msg.Marshal(nil)
+	if err != nil {
+		return TraceHop{}, err
+	}
+	if err := conn.IPv4PacketConn().SetTTL(ttl); err != nil {
+		return TraceHop{}, err
+	}
+
+	start := time.Now()
+	conn.SetReadDeadline(start.Add(defaultHopTimeout))
+	if _, err := conn.WriteTo(wb, &net.IPAddr{IP: dst}); err != nil {
+		return TraceHop{}, err
+	}
+	return awaitHop(conn, dst, ttl, start, func(m *icmp.Message, inner []byte) bool {
+		if echo, ok := m.Body.(*icmp.Echo); ok {
+			return m.Type == ipv4.ICMPTypeEchoReply && echo.ID == id && echo.Seq == ttl
+		}
+		return embeddedMatch(inner, dst, 1, func(l4 []byte) bool {
+			return int(l4[4])<<8|int(l4[5]) == id && int(l4[6])<<8|int(l4[7]) == ttl
+		})
+	})
+}
+
+func traceUDP(conn *icmp.PacketConn, dst net.IP, ttl int) (TraceHop, error) {
+	port := traceroutePortBase + ttl
+	udp, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: dst, Port: port})
+	if err != nil {
+		return TraceHop{}, err
+	}
+	defer udp.Close()
+	if err := ipv4.NewConn(udp).SetTTL(ttl); err != nil {
+		return TraceHop{}, err
+	}
+
+	start := time.Now()
+	conn.SetReadDeadline(start.Add(defaultHopTimeout))
+	if _, err := udp.Write([]byte("uptime-w33d")); err != nil {
+		return TraceHop{}, err
+	}
+	srcPort := udp.LocalAddr().(*net.UDPAddr).Port
+	return awaitHop(conn, dst, ttl, start, func(m *icmp.Message, inner []byte) bool {
+		return embeddedMatch(inner, dst, syscall.IPPROTO_UDP, func(l4 []byte) bool {
+			return int(l4[0])<<8|int(l4[1]) == srcPort && int(l4[2])<<8|int(l4[3]) == port
+		})
+	})
+}
+
+func traceTCP(conn *icmp.PacketConn, dst net.IP, port int, ttl int) (TraceHop, error) {
+	dialer := net.Dialer{
+		Timeout: defaultHopTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			var serr error
+			err := c.Control(func(fd uintptr) { serr = setTTL(fd, ttl) })
+			if err != nil {
+				return err
+			}
+			return serr
+		},
+	}
+
+	start := time.Now()
+	conn.SetReadDeadline(start.Add(defaultHopTimeout))
+	done := make(chan error, 1)
+	go func() {
+		c, err := dialer.Dial("tcp4", net.JoinHostPort(dst.String(), fmt.Sprint(port)))
+		if err == nil {
+			c.Close()
+		}
+		if err == nil || errors.Is(err, syscall.ECONNREFUSED) {
+			// The target answered, no router will: stop waiting for ICMP
+			conn.SetReadDeadline(time.Now())
+		}
+		done <- err
+	}()
+
+	hop, err := awaitHop(conn, dst, ttl, start, func(m *icmp.Message, inner []byte) bool {
+		return embeddedMatch(inner, dst, syscall.IPPROTO_TCP, func(l4 []byte) bool {
+			return int(l4[2])<<8|int(l4[3]) == port
+		})
+	})
+	dialErr := <-done
+	if err != nil || hop.Addr != "*" {
+		return hop, err
+	}
+
+	// No router spoke up: an answer from the target itself (accept or reset) means we arrived
+	if dialErr == nil || errors.Is(dialErr, syscall.ECONNREFUSED) {
+		return TraceHop{TTL: ttl, Addr: dst.String(), RTT: millis(time.Since(start)), Reached: true}, nil
+	}
+	return hop, nil
+}
+
+// awaitHop reads ICMP messages until match accepts one or the read deadline
+// set by the caller passes.
+func awaitHop(conn *icmp.PacketConn, dst net.IP, ttl int, start time.Time, match func(m *icmp.Message, inner []byte) bool) (TraceHop, error) {
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return TraceHop{TTL: ttl, Addr: "*"}, nil
+			}
+			return TraceHop{}, err
+		}
+		rtt := time.Since(start)
+
+		m, err := icmp.ParseMessage(1, buf[:n])
+		if err != nil {
+			continue
+		}
+		var inner []byte
+		unreachable := false
+		switch body := m.Body.(type) {
+		case *icmp.TimeExceeded:
+			inner = body.Data
+		case *icmp.DstUnreach:
+			inner = body.Data
+			unreachable = true
+		default:
+			// Only ICMP errors come from routers, anything else must be the target's
+			if ip, ok := peer.(*net.IPAddr); !ok || !ip.IP.Equal(dst) {
+				continue
+			}
+		}
+		if !match(m, inner) {
+			continue
+		}
+
+		hop := TraceHop{TTL: ttl, Addr: peer.String(), RTT: millis(rtt)}
+		// Echo reply or port unreachable from the target itself means we made it
+		if ip, ok := peer.(*net.IPAddr); ok && ip.IP.Equal(dst) {
+			hop.Reached = true
+		} else {
+			hop.Unreachable = unreachable
+		}
+		return hop, nil
+	}
+}
+
+// embeddedMatch checks the original datagram quoted in an ICMP error: its
+// destination, protocol and the first transport bytes.
+func embeddedMatch(inner []byte, dst net.IP, proto int, check func(l4 []byte) bool) bool {
+	if len(inner) < 20 {
+		return false
+	}
+	ihl := int(inner[0]&0x0f) * 4
+	if len(inner) < ihl+8 || int(inner[9]) != proto || !net.IP(inner[16:20]).Equal(dst) {
+		return false
+	}
+	return check(inner[ihl:])
+}
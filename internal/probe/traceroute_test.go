@@ -0,0 +1,52 @@
+package probe_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"uptime_w33d/internal/probe"
+)
+
+func TestTraceroute_Localhost(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	for _, method := range []string{"icmp", "udp", "tcp"} {
+		hops, err := probe.Traceroute("127.0.0.1", port, method, 5)
+		if err != nil && errors.Is(err, os.ErrPermission) {
+			t.Skipf("raw ICMP not available: %v", err)
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", method, err)
+		}
+		if len(hops) != 1 || !hops[0].Reached || hops[0].Addr != "127.0.0.1" {
+			t.Errorf("%s: expected a single reached hop, got %+v", method, hops)
+		}
+	}
+
+	if _, err := probe.Traceroute("127.0.0.1", 0, "carrier-pigeon", 5); err == nil {
+		t.Errorf("Expected error for unknown method")
+	}
+}
+
+func TestFormatHops(t *testing.T) {
+	out := probe.FormatHops([]probe.TraceHop{
+		{TTL: 1, Addr: "192.168.1.1", RTT: 0.42},
+		{TTL: 2, Addr: "*"},
+		{TTL: 3, Addr: "203.0.113.9", RTT: 12.5, Reached: true},
+	})
+	want := " 1  192.168.1.1  0.42ms\n 2  *\n 3  203.0.113.9  12.50ms"
+	if out != want {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+	if strings.Contains(out, "unreachable") {
+		t.Errorf("Did not expect unreachable marker")
+	}
+}
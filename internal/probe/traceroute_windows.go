@@ -0,0 +1,9 @@
+//go:build windows
+
+package probe
+
+import "syscall"
+
+func setTTL(fd uintptr, ttl int) error {
+	return syscall.SetsockoptInt(syscall.Handle(fd), syscall.IPPROTO_IP, syscall.IP_TTL, ttl)
+}
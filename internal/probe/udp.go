@@ -0,0 +1,93 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"uptime_w33d/internal/models"
+)
+
+// udpUnreachableWait is how long we listen for an ICMP port unreachable
+// when no reply is expected, UDP has no handshake to tell us otherwise.
+const udpUnreachableWait = 500 * time.Millisecond
+
+type UDPProbe struct {
+	BaseProbe
+}
+
+func NewUDPProbe() *UDPProbe {
+	return &UDPProbe{}
+}
+
+func (p *UDPProbe) Type() models.MonitorType {
+	return models.TypeUDP
+}
+
+func (p *UDPProbe) Check(monitor models.Monitor) Result {
+	start := time.Now()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	payload, err := decodePayload(monitor.SendPayload, monitor.PayloadFormat)
+	if err != nil {
+		return p.RecordResult(false, err.Error(), 0)
+	}
+	var matcher *responseMatcher
+	if monitor.ExpectResponse != "" {
+		if matcher, err = newResponseMatcher(monitor.ExpectResponse, monitor.ExpectMode); err != nil {
+			return p.RecordResult(false, err.Error(), 0)
+		}
+	}
+
+	conn, err := net.DialTimeout("udp", monitor.Target, timeout)
+	if err != nil {
+		return p.RecordResult(false, fmt.Sprintf("invalid address: %v", err), 0)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(payload); err != nil {
+		return p.RecordResult(false, fmt.Sprintf("send failed: %v", err), time.Since(start))
+	}
+
+	readTimeout := timeout
+	if monitor.ReadTimeout > 0 {
+		readTimeout = time.Duration(monitor.ReadTimeout) * time.Second
+	}
+	if matcher == nil && readTimeout > udpUnreachableWait {
+		readTimeout = udpUnreachableWait
+	}
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+
+	// Read datagrams until one matches or the deadline passes
+	buf := make([]byte, 65535)
+	var last []byte
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			// Typically ICMP port unreachable surfaced as "connection refused"
+			return p.RecordResult(false, fmt.Sprintf("read failed: %v", err), time.Since(start))
+		}
+		last = append(last[:0], buf[:n]...)
+		if matcher == nil || matcher.Match(last) {
+			res := p.RecordResult(true, fmt.Sprintf("Reply received: %s", printable(last, 100)), time.Since(start))
+			res.Data["response"] = printable(last, 1024)
+			return res
+		}
+	}
+
+	if matcher == nil {
+		// Nothing came back, but nothing refused the packet either
+		return p.RecordResult(true, fmt.Sprintf("Sent %d bytes, no reply expected", len(payload)), time.Since(start))
+	}
+
+	msg := "no reply within timeout"
+	if last != nil {
+		msg = fmt.Sprintf("unexpected reply: %s", printable(last, 100))
+	}
+	return p.RecordResult(false, msg, time.Since(start))
+}
@@ -0,0 +1,86 @@
+package probe_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func startUDPEcho(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if bytes.Equal(buf[:n], []byte{0xde, 0xad}) {
+				conn.WriteTo([]byte{0xbe, 0xef, 0x01}, addr)
+				continue
+			}
+			conn.WriteTo(append([]byte("ECHO "), buf[:n]...), addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestUDPProbe_Check_Reply(t *testing.T) {
+	addr := startUDPEcho(t)
+
+	p := probe.NewUDPProbe()
+	m := models.Monitor{
+		Type:           models.TypeUDP,
+		Target:         addr,
+		Timeout:        1,
+		SendPayload:    "status",
+		ExpectResponse: "ECHO status",
+	}
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success, got failure: %s", result.Message)
+	}
+
+	m.SendPayload = "de ad"
+	m.PayloadFormat = "hex"
+	m.ExpectResponse = "beef"
+	m.ExpectMode = "hex"
+	if result := p.Check(m); !result.Success {
+		t.Errorf("Expected success with hex payload, got failure: %s", result.Message)
+	}
+
+	m.ExpectResponse = "cafe"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for unexpected reply, got success")
+	}
+}
+
+func TestUDPProbe_Check_NoListener(t *testing.T) {
+	// Grab a free port and close it so nothing is listening
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := conn.LocalAddr().String()
+	conn.Close()
+
+	p := probe.NewUDPProbe()
+	m := models.Monitor{
+		Type:           models.TypeUDP,
+		Target:         addr,
+		Timeout:        1,
+		SendPayload:    "ping",
+		ExpectResponse: "pong",
+	}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure with no listener, got success")
+	}
+}
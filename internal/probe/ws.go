@@ -2,11 +2,14 @@ package probe
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/tidwall/gjson"
 	"uptime_w33d/internal/models"
 )
 
@@ -28,11 +31,30 @@ func (p *WSProbe) Check(monitor models.Monitor) Result {
 
 	dialer := websocket.Dialer{
 		HandshakeTimeout: timeout,
-		TLSClientConfig:  &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig:  &tls.Config{InsecureSkipVerify: !monitor.VerifyTLS},
+	}
+
+	// Subprotocols, comma separated
+	if monitor.Subprotocols != "" {
+		for _, proto := range strings.Split(monitor.Subprotocols, ",") {
+			if proto = strings.TrimSpace(proto); proto != "" {
+				dialer.Subprotocols = append(dialer.Subprotocols, proto)
+			}
+		}
+	}
+
+	header := http.Header{"User-Agent": []string{"UptimeW33d/1.0"}}
+	if monitor.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(monitor.Headers), &headers); err == nil {
+			for k, v := range headers {
+				header.Set(k, v)
+			}
+		}
 	}
 
 	// Connect
-	conn, resp, err := dialer.Dial(monitor.Target, http.Header{"User-Agent": []string{"UptimeW33d/1.0"}})
+	conn, resp, err := dialer.Dial(monitor.Target, header)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -44,17 +66,75 @@ func (p *WSProbe) Check(monitor models.Monitor) Result {
 	}
 	defer conn.Close()
 
-	// Optional: Send a ping or verify handshake?
-	// For now, successful connection is enough.
-	
+	if len(dialer.Subprotocols) > 0 && conn.Subprotocol() == "" {
+		return p.RecordResult(false, fmt.Sprintf("server accepted none of the subprotocols: %s", monitor.Subprotocols), duration)
+	}
+
 	msg := "Connected"
 	if resp != nil {
 		msg = fmt.Sprintf("Connected (HTTP %d)", resp.StatusCode)
 	}
 
-	res := p.RecordResult(true, msg, duration)
+	// Message Exchange: send SendPayload, then check the reply
+	var reply []byte
+	if monitor.SendPayload != "" {
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(monitor.SendPayload)); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("send failed: %v", err), time.Since(start))
+		}
+	}
+	if monitor.SendPayload != "" || monitor.ExpectResponse != "" || monitor.JSONPath != "" {
+		readTimeout := timeout
+		if monitor.ReadTimeout > 0 {
+			readTimeout = time.Duration(monitor.ReadTimeout) * time.Second
+		}
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		if _, reply, err = conn.ReadMessage(); err != nil {
+			return p.RecordResult(false, fmt.Sprintf("no reply: %v", err), time.Since(start))
+		}
+		duration = time.Since(start)
+		msg = fmt.Sprintf("Reply received: %s", printable(reply, 100))
+	}
+
+	success := true
+	if reply != nil {
+		// Keyword / Regex check
+		if monitor.ExpectResponse != "" {
+			matcher, err := newResponseMatcher(monitor.ExpectResponse, monitor.ExpectMode)
+			if err != nil {
+				return p.RecordResult(false, err.Error(), duration)
+			}
+			if !matcher.Match(reply) {
+				success = false
+				msg = fmt.Sprintf("unexpected reply: %s", printable(reply, 100))
+			}
+		}
+
+		// JSON Query Check
+		if success && monitor.JSONPath != "" {
+			res := gjson.GetBytes(reply, monitor.JSONPath)
+			if !res.Exists() {
+				success = false
+				msg = fmt.Sprintf("JSON Path '%s' not found", monitor.JSONPath)
+			} else if monitor.JSONValue != "" && res.String() != monitor.JSONValue {
+				success = false
+				msg = fmt.Sprintf("JSON Value mismatch: expected '%s', got '%s'", monitor.JSONValue, res.String())
+			}
+		}
+	}
+
+	res := p.RecordResult(success, msg, duration)
 	if resp != nil {
 		res.Data["status_code"] = resp.StatusCode
 	}
+	if proto := conn.Subprotocol(); proto != "" {
+		res.Data["subprotocol"] = proto
+	}
+	if reply != nil {
+		res.Data["response"] = printable(reply, 1024)
+	}
+	if tlsConn, ok := conn.NetConn().(*tls.Conn); ok {
+		recordTLSState(tlsConn.ConnectionState(), res.Data)
+	}
 	return res
 }
@@ -0,0 +1,95 @@
+package probe_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+)
+
+func newWSServer() *httptest.Server {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"graphql-ws"}}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if string(msg) == `{"type":"ping"}` {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"pong","server":{"healthy":true}}`))
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"error"}`))
+	}))
+}
+
+func TestWSProbe_Check_MessageExchange(t *testing.T) {
+	ts := newWSServer()
+	defer ts.Close()
+
+	p := probe.NewWSProbe()
+	m := models.Monitor{
+		Type:           models.TypeWS,
+		Target:         "ws" + strings.TrimPrefix(ts.URL, "http"),
+		Timeout:        1,
+		Headers:        `{"Authorization":"Bearer token"}`,
+		Subprotocols:   "graphql-ws",
+		SendPayload:    `{"type":"ping"}`,
+		ExpectResponse: `"type":"pong"`,
+		JSONPath:       "server.healthy",
+		JSONValue:      "true",
+	}
+	result := p.Check(m)
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %s", result.Message)
+	}
+	if result.Data["subprotocol"] != "graphql-ws" {
+		t.Errorf("Expected negotiated subprotocol, got %v", result.Data["subprotocol"])
+	}
+
+	m.SendPayload = `{"type":"hello"}`
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for unexpected reply, got success")
+	}
+
+	m.SendPayload = `{"type":"ping"}`
+	m.ExpectResponse = `^\{"type":"pong"`
+	m.ExpectMode = "regex"
+	m.JSONValue = "false"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure for JSON value mismatch, got success")
+	}
+
+	m.Subprotocols = "mqtt"
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure when subprotocol is not accepted, got success")
+	}
+}
+
+func TestWSProbe_Check_HandshakeRejected(t *testing.T) {
+	ts := newWSServer()
+	defer ts.Close()
+
+	p := probe.NewWSProbe()
+	m := models.Monitor{
+		Type:    models.TypeWS,
+		Target:  "ws" + strings.TrimPrefix(ts.URL, "http"),
+		Timeout: 1,
+	}
+	if result := p.Check(m); result.Success {
+		t.Errorf("Expected failure without Authorization header, got success")
+	}
+}
@@ -14,6 +14,9 @@ type MonitorRepository interface {
 	GetByPushToken(token string) (*models.Monitor, error)
 	GetAll(userID uint) ([]models.Monitor, error)
 	Update(monitor *models.Monitor) error
+	UpdateColumns(id uint, values map[string]interface{}) error
+	PinBaseline(id uint, target string, column string, value string) error
+	PinRestartCount(id uint, target string, count int) error
 	Delete(id uint) error
 }
 
@@ -63,6 +66,31 @@ func (r *monitorRepository) Update(monitor *models.Monitor) error {
 	return r.db.Save(monitor).Error
 }
 
+// UpdateColumns writes only the given columns, e.g. what a finished check
+// owns (last_status, last_checked_at, expiries...) or an acknowledgement.
+// Saving the whole monitor would undo concurrent edits, checks and re-pins.
+func (r *monitorRepository) UpdateColumns(id uint, values map[string]interface{}) error {
+	return r.db.Model(&models.Monitor{}).Where("id = ?", id).Updates(values).Error
+}
+
+// PinBaseline sets a string baseline (content_hash, host_key_fingerprint)
+// unless it was pinned meanwhile or the target changed since the check read it.
+func (r *monitorRepository) PinBaseline(id uint, target string, column string, value string) error {
+	return r.db.Model(&models.Monitor{}).
+		Where("id = ? AND target = ?", id, target).
+		Where(r.db.Where(column+" = ''").Or(column+" IS NULL")).
+		Update(column, value).Error
+}
+
+// PinRestartCount sets the container restart baseline when there is none or
+// the container was recreated (lower count).
+func (r *monitorRepository) PinRestartCount(id uint, target string, count int) error {
+	return r.db.Model(&models.Monitor{}).
+		Where("id = ? AND target = ?", id, target).
+		Where("restart_count IS NULL OR restart_count > ?", count).
+		Update("restart_count", count).Error
+}
+
 func (r *monitorRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Monitor{}, id).Error
 }
@@ -14,6 +14,7 @@ type StatusPageRepository interface {
 	GetAll() ([]models.StatusPage, error)
 	Update(page *models.StatusPage) error
 	Delete(id uint) error
+	HasMonitor(monitorID uint) (bool, error)
 }
 
 type statusPageRepository struct {
@@ -72,3 +73,13 @@ func (r *statusPageRepository) Update(page *models.StatusPage) error {
 func (r *statusPageRepository) Delete(id uint) error {
 	return r.db.Delete(&models.StatusPage{}, id).Error
 }
+
+// HasMonitor reports whether the monitor is listed on any public status page.
+func (r *statusPageRepository) HasMonitor(monitorID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.StatusPage{}).
+		Joins("JOIN status_page_monitors ON status_page_monitors.status_page_id = status_pages.id").
+		Where("status_page_monitors.monitor_id = ? AND status_pages.public = ?", monitorID, true).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -1,6 +1,9 @@
 package scheduler
 
 import (
+	"net"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"uptime_w33d/internal/services"
 	"uptime_w33d/pkg/cache"
 	"uptime_w33d/pkg/logger"
+	"uptime_w33d/pkg/utils"
 )
 
 type Scheduler struct {
@@ -21,6 +25,7 @@ type Scheduler struct {
 	probes      map[models.MonitorType]probe.Probe
 	stopChan    chan struct{}
 	wg          sync.WaitGroup
+	running     sync.Map // Monitor IDs with a check in flight
 }
 
 func NewScheduler(monitorRepo repository.MonitorRepository, resultRepo repository.CheckResultRepository, notifySvc services.NotificationService) *Scheduler {
@@ -40,12 +45,29 @@ func NewScheduler(monitorRepo repository.MonitorRepository, resultRepo repositor
 	s.probes[models.TypeHTTPKeyword] = httpProbe
 	s.probes[models.TypeHTTPJson] = httpProbe
 
+	s.RegisterProbe(probe.NewHTTPStepsProbe())
 	s.RegisterProbe(probe.NewTCPProbe())
+	s.RegisterProbe(probe.NewUDPProbe())
 	s.RegisterProbe(probe.NewWSProbe())
 	s.RegisterProbe(probe.NewSteamProbe())
 	s.RegisterProbe(probe.NewDockerProbe())
 	s.RegisterProbe(probe.NewPingProbe())
 	s.RegisterProbe(probe.NewDNSProbe())
+	s.RegisterProbe(probe.NewGRPCProbe())
+	s.RegisterProbe(probe.NewPostgresProbe())
+	s.RegisterProbe(probe.NewMySQLProbe())
+	s.RegisterProbe(probe.NewRedisProbe())
+	s.RegisterProbe(probe.NewSMTPProbe())
+	s.RegisterProbe(probe.NewIMAPProbe())
+	s.RegisterProbe(probe.NewPOP3Probe())
+	s.RegisterProbe(probe.NewSSHProbe())
+	s.RegisterProbe(probe.NewMQTTProbe())
+	s.RegisterProbe(probe.NewMinecraftProbe())
+	s.RegisterProbe(probe.NewPrometheusProbe())
+	s.RegisterProbe(probe.NewGraphQLProbe())
+	s.RegisterProbe(probe.NewDomainProbe())
+	s.RegisterProbe(probe.NewNTPProbe())
+	s.RegisterProbe(probe.NewLDAPProbe())
 
 	return s
 }
@@ -99,9 +121,16 @@ func (s *Scheduler) scheduleJobs() {
 		}
 		
 		// TODO: Check last run time and interval logic
+
+		// Retries and traceroutes can outlast the tick. A second check would
+		// still see the old LastStatus and notify (and trace) again.
+		if _, busy := s.running.LoadOrStore(m.ID, struct{}{}); busy {
+			continue
+		}
 		s.wg.Add(1)
 		go func(monitor models.Monitor) {
 			defer s.wg.Done()
+			defer s.running.Delete(monitor.ID)
 			s.executeCheck(monitor)
 		}(m)
 	}
@@ -125,7 +154,7 @@ func (s *Scheduler) checkPushMonitor(m models.Monitor) {
 			m.LastStatus = "down"
 			// Don't update LastCheckedAt so we know when it actually last checked in
 			
-			if err := s.monitorRepo.Update(&m); err != nil {
+			if err := s.monitorRepo.UpdateColumns(m.ID, map[string]interface{}{"last_status": m.LastStatus}); err != nil {
 				logger.Log.Error("Failed to update push monitor status", zap.Error(err))
 			}
 			
@@ -153,7 +182,7 @@ func (s *Scheduler) executeCheck(m models.Monitor) {
 		return
 	}
 
-	logger.Log.Debug("Executing check", zap.String("monitor", m.Name), zap.String("target", m.Target))
+	logger.Log.Debug("Executing check", zap.String("monitor", m.Name), zap.String("target", utils.RedactTarget(m.Target)))
 	
 	// Execute Probe with Retry Logic
 	var result probe.Result
@@ -186,6 +215,8 @@ func (s *Scheduler) executeCheck(m models.Monitor) {
 	status := "up"
 	if !result.Success {
 		status = "down"
+	} else if result.Degraded {
+		status = "degraded"
 	}
 
 	// 1. Save Result
@@ -194,8 +225,18 @@ func (s *Scheduler) executeCheck(m models.Monitor) {
 		Status:       status,
 		ResponseTime: result.ResponseTime.Milliseconds(),
 		Message:      result.Message,
+		Data:         result.Data,
 		CreatedAt:    time.Now(),
 	}
+	applyTimings(checkResult, result.Data)
+
+	// Diagnose fresh outages while the path is still broken
+	message := result.Message
+	if status == "down" && m.LastStatus != status && m.Traceroute != "" {
+		if summary := s.traceroute(m, checkResult); summary != "" {
+			message += "\n\nTraceroute:\n" + summary
+		}
+	}
 	
 	if err := s.resultRepo.Create(checkResult); err != nil {
 		logger.Log.Error("Failed to save check result", zap.Error(err))
@@ -213,27 +254,68 @@ func (s *Scheduler) executeCheck(m models.Monitor) {
 			zap.String("new_status", status),
 		)
 		
-		s.notifySvc.Notify(m, status, result.Message)
+		s.notifySvc.Notify(m, status, message)
 		
 		// Invalidate Cache
 		_ = cache.Delete("public_status_page_default")
 	}
 
-	// 3. Update Monitor Last Status
-	m.LastStatus = status
+	// 3. Update Monitor Last Status. Only the columns the check owns are
+	// written, the monitor may have been edited or acknowledged meanwhile.
 	now := time.Now()
-	m.LastCheckedAt = &now
+	state := map[string]interface{}{
+		"last_status":     status,
+		"last_checked_at": &now,
+	}
 	
 	// Update Certificate Expiry if available
 	if val, ok := result.Data["cert_expiry"]; ok {
 		if t, ok := val.(time.Time); ok {
-			m.CertificateExpiry = &t
+			state["certificate_expiry"] = &t
 		}
 	}
 	
-	if err := s.monitorRepo.Update(&m); err != nil {
+	// Update Domain Expiry if available
+	if val, ok := result.Data["domain_expiry"]; ok {
+		if t, ok := val.(time.Time); ok {
+			state["domain_expiry"] = &t
+		}
+	}
+
+	// Remember since when a game server is full or empty
+	if val, ok := result.Data["population"].(string); ok && val != m.PopulationState {
+		state["population_state"] = val
+		state["population_since"] = nil
+		if val != "" {
+			state["population_since"] = &now
+		}
+	}
+
+	if err := s.monitorRepo.UpdateColumns(m.ID, state); err != nil {
 		logger.Log.Error("Failed to update monitor status", zap.Error(err))
 	}
+
+	// Pin the SSH host key on first contact
+	if val, ok := result.Data["host_key_fingerprint"].(string); ok && m.HostKeyFingerprint == "" {
+		if err := s.monitorRepo.PinBaseline(m.ID, m.Target, "host_key_fingerprint", val); err != nil {
+			logger.Log.Error("Failed to pin host key", zap.Error(err))
+		}
+	}
+
+	// Pin the container restart count. Restarts keep the monitor down until
+	// acknowledged, only a recreated container (lower count) moves it.
+	if val, ok := result.Data["restart_count"].(int); ok && (m.RestartCount == nil || val < *m.RestartCount) {
+		if err := s.monitorRepo.PinRestartCount(m.ID, m.Target, val); err != nil {
+			logger.Log.Error("Failed to pin restart count", zap.Error(err))
+		}
+	}
+
+	// Pin the content hash for change detection, a change stays down until acknowledged
+	if val, ok := result.Data["content_hash"].(string); ok && m.ContentHash == "" {
+		if err := s.monitorRepo.PinBaseline(m.ID, m.Target, "content_hash", val); err != nil {
+			logger.Log.Error("Failed to pin content hash", zap.Error(err))
+		}
+	}
 	
 	logLevel := zap.InfoLevel
 	if !result.Success {
@@ -247,3 +329,70 @@ func (s *Scheduler) executeCheck(m models.Monitor) {
 		zap.String("msg", result.Message),
 	)
 }
+
+// applyTimings copies the HTTP phase durations reported by the probe into the stored result.
+func applyTimings(cr *models.CheckResult, data map[string]interface{}) {
+	ms := func(key string) int64 {
+		if d, ok := data[key].(time.Duration); ok {
+			return d.Milliseconds()
+		}
+		return 0
+	}
+	cr.DNSTime = ms("timing_dns")
+	cr.ConnectTime = ms("timing_connect")
+	cr.TLSTime = ms("timing_tls")
+	cr.TTFBTime = ms("timing_ttfb")
+	cr.TransferTime = ms("timing_transfer")
+}
+
+// traceroute runs the monitor's traceroute, stores the hops on the check
+// result and returns a printable summary ("" if it could not run).
+func (s *Scheduler) traceroute(m models.Monitor, cr *models.CheckResult) string {
+	host, port, ok := traceTarget(m)
+	if !ok {
+		return ""
+	}
+
+	hops, err := probe.Traceroute(host, port, m.Traceroute, 0)
+	if err != nil {
+		logger.Log.Warn("Traceroute failed", zap.String("monitor", m.Name), zap.Error(err))
+		if len(hops) == 0 {
+			return ""
+		}
+	}
+
+	if cr.Data == nil {
+		cr.Data = make(map[string]interface{})
+	}
+	cr.Data["traceroute"] = hops
+	return probe.FormatHops(hops)
+}
+
+// traceTarget picks the host (and port for TCP SYN traces) out of a monitor target.
+func traceTarget(m models.Monitor) (string, int, bool) {
+	switch m.Type {
+	case models.TypePing:
+		return m.Target, 0, true
+	case models.TypeTCP:
+		host, port, err := net.SplitHostPort(m.Target)
+		if err != nil {
+			return m.Target, 0, true
+		}
+		p, _ := strconv.Atoi(port)
+		return host, p, true
+	case models.TypeHTTP, models.TypeHTTPKeyword, models.TypeHTTPJson:
+		u, err := url.Parse(m.Target)
+		if err != nil || u.Hostname() == "" {
+			return "", 0, false
+		}
+		port := 80
+		if u.Scheme == "https" {
+			port = 443
+		}
+		if p, err := strconv.Atoi(u.Port()); err == nil {
+			port = p
+		}
+		return u.Hostname(), port, true
+	}
+	return "", 0, false
+}
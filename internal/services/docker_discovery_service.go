@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/zap"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+	"uptime_w33d/internal/repository"
+	"uptime_w33d/pkg/logger"
+)
+
+// nameLabel overrides the monitor name of a discovered container.
+const nameLabel = "uptime.name"
+
+type DockerDiscoveryService interface {
+	Sync() error
+	Run(interval time.Duration, stop <-chan struct{})
+}
+
+type dockerDiscoveryService struct {
+	monitorRepo repository.MonitorRepository
+	host        string
+	label       string
+}
+
+// NewDockerDiscoveryService keeps a docker monitor for every container on host
+// that carries label ("key" or "key=value").
+func NewDockerDiscoveryService(monitorRepo repository.MonitorRepository, host string, label string) DockerDiscoveryService {
+	if host == "" {
+		host = "local"
+	}
+	return &dockerDiscoveryService{
+		monitorRepo: monitorRepo,
+		host:        host,
+		label:       label,
+	}
+}
+
+func (s *dockerDiscoveryService) Run(interval time.Duration, stop <-chan struct{}) {
+	if err := s.Sync(); err != nil {
+		logger.Log.Error("Docker discovery failed", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Sync(); err != nil {
+				logger.Log.Error("Docker discovery failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Sync creates monitors for labelled containers that have none yet and
+// removes discovered monitors whose container is gone. Stopped containers
+// keep their monitor so they show up as down.
+func (s *dockerDiscoveryService) Sync() error {
+	cli, err := probe.NewDockerClient(s.host)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", s.label)),
+	})
+	if err != nil {
+		return err
+	}
+
+	monitors, err := s.monitorRepo.GetAll(0)
+	if err != nil {
+		return err
+	}
+
+	// Containers already covered by a monitor on this host, discovered or not
+	known := make(map[string]models.Monitor)
+	for _, m := range monitors {
+		if m.Type != models.TypeDocker || probe.DockerHost(m.Target) != probe.DockerHost(s.host) {
+			continue
+		}
+		name := m.Container
+		if name == "" {
+			name = m.Keyword
+		}
+		known[name] = m
+	}
+
+	found := make(map[string]bool)
+	for _, c := range containers {
+		if len(c.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(c.Names[0], "/")
+		found[name] = true
+		if _, ok := known[name]; ok {
+			continue
+		}
+
+		monitorName := c.Labels[nameLabel]
+		if monitorName == "" {
+			monitorName = name
+		}
+		monitor := &models.Monitor{
+			Name:           monitorName,
+			Type:           models.TypeDocker,
+			Target:         s.host,
+			Container:      name,
+			Interval:       60,
+			Timeout:        10,
+			MaxRetries:     1,
+			Enabled:        true,
+			AutoDiscovered: true,
+		}
+		if err := s.monitorRepo.Create(monitor); err != nil {
+			return err
+		}
+		logger.Log.Info("Discovered container", zap.String("container", name), zap.Uint("monitor_id", monitor.ID))
+	}
+
+	for name, m := range known {
+		if !m.AutoDiscovered || found[name] {
+			continue
+		}
+		if err := s.monitorRepo.Delete(m.ID); err != nil {
+			return err
+		}
+		logger.Log.Info("Removed monitor for vanished container", zap.String("container", name), zap.Uint("monitor_id", m.ID))
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/services"
+	"uptime_w33d/pkg/logger"
+)
+
+// memMonitorRepo is a minimal in-memory MonitorRepository.
+type memMonitorRepo struct {
+	monitors map[uint]models.Monitor
+	nextID   uint
+}
+
+func (r *memMonitorRepo) Create(m *models.Monitor) error {
+	r.nextID++
+	m.ID = r.nextID
+	r.monitors[m.ID] = *m
+	return nil
+}
+func (r *memMonitorRepo) GetByID(id uint) (*models.Monitor, error) {
+	m, ok := r.monitors[id]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+func (r *memMonitorRepo) GetByPushToken(token string) (*models.Monitor, error) { return nil, nil }
+func (r *memMonitorRepo) Update(m *models.Monitor) error {
+	r.monitors[m.ID] = *m
+	return nil
+}
+func (r *memMonitorRepo) UpdateColumns(id uint, values map[string]interface{}) error {
+	m := r.monitors[id]
+	for column, value := range values {
+		switch column {
+		case "restart_count":
+			m.RestartCount, _ = value.(*int)
+		case "content_hash":
+			m.ContentHash = value.(string)
+		case "host_key_fingerprint":
+			m.HostKeyFingerprint = value.(string)
+		}
+	}
+	r.monitors[id] = m
+	return nil
+}
+func (r *memMonitorRepo) PinBaseline(id uint, target string, column string, value string) error {
+	return nil
+}
+func (r *memMonitorRepo) PinRestartCount(id uint, target string, count int) error { return nil }
+func (r *memMonitorRepo) Delete(id uint) error {
+	delete(r.monitors, id)
+	return nil
+}
+func (r *memMonitorRepo) GetAll(userID uint) ([]models.Monitor, error) {
+	var list []models.Monitor
+	for _, m := range r.monitors {
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+func (r *memMonitorRepo) containers() []string {
+	var names []string
+	for _, m := range r.monitors {
+		names = append(names, m.Container+m.Keyword)
+	}
+	return names
+}
+
+func TestDockerDiscovery_Sync(t *testing.T) {
+	logger.InitLogger("info", "console")
+
+	var listed []map[string]interface{}
+	var filter string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.47")
+		if strings.HasSuffix(r.URL.Path, "/containers/json") {
+			filter = r.URL.Query().Get("filters")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(listed)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer ts.Close()
+	host := "tcp://" + strings.TrimPrefix(ts.URL, "http://")
+
+	repo := &memMonitorRepo{monitors: map[uint]models.Monitor{}}
+	// A hand-made monitor for "db" must be left alone
+	repo.Create(&models.Monitor{Name: "db", Type: models.TypeDocker, Target: host, Keyword: "db"})
+
+	svc := services.NewDockerDiscoveryService(repo, host, "uptime.monitor=true")
+
+	listed = []map[string]interface{}{
+		{"Id": "1", "Names": []string{"/web"}, "Labels": map[string]string{"uptime.monitor": "true", "uptime.name": "Website"}},
+		{"Id": "2", "Names": []string{"/db"}, "Labels": map[string]string{"uptime.monitor": "true"}},
+	}
+	assert.NoError(t, svc.Sync())
+	assert.Contains(t, filter, "uptime.monitor=true")
+	assert.ElementsMatch(t, []string{"db", "web"}, repo.containers())
+	for _, m := range repo.monitors {
+		if m.Container == "web" {
+			assert.Equal(t, "Website", m.Name)
+			assert.True(t, m.AutoDiscovered)
+		}
+	}
+
+	// Running again does not duplicate
+	assert.NoError(t, svc.Sync())
+	assert.Len(t, repo.monitors, 2)
+
+	// Containers gone: only the discovered monitor is removed
+	listed = nil
+	assert.NoError(t, svc.Sync())
+	assert.ElementsMatch(t, []string{"db"}, repo.containers())
+}
@@ -5,6 +5,7 @@ import (
 
 	"uptime_w33d/internal/models"
 	"uptime_w33d/internal/repository"
+	"uptime_w33d/pkg/utils"
 )
 
 type MonitorService interface {
@@ -13,6 +14,8 @@ type MonitorService interface {
 	ListMonitors(userID uint) ([]models.Monitor, error)
 	UpdateMonitor(id uint, monitor *models.Monitor) error
 	DeleteMonitor(id uint) error
+	AcknowledgeMonitor(id uint) error
+	RepinHostKey(id uint) error
 }
 
 type monitorService struct {
@@ -50,6 +53,24 @@ func (s *monitorService) UpdateMonitor(id uint, updates *models.Monitor) error {
 	if existing == nil {
 		return errors.New("monitor not found")
 	}
+	// Targets are returned with their DSN password masked, sending that
+	// back keeps the stored one
+	if updates.Target == utils.RedactTarget(existing.Target) {
+		updates.Target = existing.Target
+	}
+
+	// Re-baseline content hash if what we hash has changed
+	if existing.Target != updates.Target || existing.HashSelector != updates.HashSelector || !updates.DetectChanges {
+		existing.ContentHash = ""
+	}
+	// Same for the container restart baseline
+	if existing.Target != updates.Target || existing.Container != updates.Container {
+		existing.RestartCount = nil
+	}
+	if existing.Target != updates.Target {
+		existing.PopulationState = ""
+		existing.PopulationSince = nil
+	}
 
 	// Update fields
 	existing.Name = updates.Name
@@ -62,9 +83,77 @@ func (s *monitorService) UpdateMonitor(id uint, updates *models.Monitor) error {
 	existing.Headers = updates.Headers
 	existing.Body = updates.Body
 	existing.Keyword = updates.Keyword
+	existing.InvertKeyword = updates.InvertKeyword
 	existing.JSONPath = updates.JSONPath
 	existing.JSONValue = updates.JSONValue
 	existing.ExpectedStatus = updates.ExpectedStatus
+	existing.MaxRedirects = updates.MaxRedirects
+	existing.ExpectedLocation = updates.ExpectedLocation
+	existing.Proxy = updates.Proxy
+	existing.HTTPVersion = updates.HTTPVersion
+	existing.IPVersion = updates.IPVersion
+	existing.Assertions = updates.Assertions
+	existing.MaxBodySize = updates.MaxBodySize
+	existing.DetectChanges = updates.DetectChanges
+	existing.HashSelector = updates.HashSelector
+	existing.Steps = updates.Steps
+	existing.SendPayload = updates.SendPayload
+	existing.PayloadFormat = updates.PayloadFormat
+	existing.ExpectResponse = updates.ExpectResponse
+	existing.ExpectMode = updates.ExpectMode
+	existing.ReadTimeout = updates.ReadTimeout
+	existing.UseTLS = updates.UseTLS
+	existing.VerifyTLS = updates.VerifyTLS
+	existing.StartTLS = updates.StartTLS
+	existing.GRPCService = updates.GRPCService
+	existing.Username = updates.Username
+	// Secrets are never returned, an empty value means "unchanged" unless
+	// the client asks to clear them
+	if updates.Password != "" || updates.ClearPassword {
+		existing.Password = updates.Password
+	}
+	if updates.PrivateKey != "" || updates.ClearPrivateKey {
+		existing.PrivateKey = updates.PrivateKey
+	}
+	// The pinned host key only moves when a new one is sent or on re-pin
+	if updates.HostKeyFingerprint != "" {
+		existing.HostKeyFingerprint = updates.HostKeyFingerprint
+	}
+	existing.Query = updates.Query
+	existing.ExpectedValue = updates.ExpectedValue
+	existing.Topic = updates.Topic
+	existing.Subprotocols = updates.Subprotocols
+	existing.Container = updates.Container
+	existing.PingCount = updates.PingCount
+	existing.PingInterval = updates.PingInterval
+	existing.PacketSize = updates.PacketSize
+	existing.DegradedLoss = updates.DegradedLoss
+	existing.DownLoss = updates.DownLoss
+	existing.DegradedJitter = updates.DegradedJitter
+	existing.DownJitter = updates.DownJitter
+	existing.Traceroute = updates.Traceroute
+	existing.MinPlayers = updates.MinPlayers
+	existing.MaxPlayers = updates.MaxPlayers
+	existing.ExpectedMap = updates.ExpectedMap
+	existing.ServerNamePattern = updates.ServerNamePattern
+	existing.ExpectPassword = updates.ExpectPassword
+	existing.ExpectVAC = updates.ExpectVAC
+	existing.QueryPlayers = updates.QueryPlayers
+	existing.QueryRules = updates.QueryRules
+	existing.FullAlertAfter = updates.FullAlertAfter
+	existing.EmptyAlertAfter = updates.EmptyAlertAfter
+	existing.Edition = updates.Edition
+	existing.Arguments = updates.Arguments
+	existing.Environment = updates.Environment
+	existing.WorkingDir = updates.WorkingDir
+	existing.Variables = updates.Variables
+	existing.RDAPServer = updates.RDAPServer
+	existing.WHOISServer = updates.WHOISServer
+	existing.ExpiryWarnDays = updates.ExpiryWarnDays
+	existing.ExpiryCriticalDays = updates.ExpiryCriticalDays
+	existing.MaxOffset = updates.MaxOffset
+	existing.SearchBase = updates.SearchBase
+	existing.MinEntries = updates.MinEntries
 	existing.IsPublic = updates.IsPublic
 	existing.Enabled = updates.Enabled
 	existing.GroupID = updates.GroupID
@@ -75,3 +164,32 @@ func (s *monitorService) UpdateMonitor(id uint, updates *models.Monitor) error {
 func (s *monitorService) DeleteMonitor(id uint) error {
 	return s.monitorRepo.Delete(id)
 }
+
+// AcknowledgeMonitor clears the change baselines so the next check pins the
+// current state, ending a restart or content change alert.
+func (s *monitorService) AcknowledgeMonitor(id uint) error {
+	existing, err := s.monitorRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("monitor not found")
+	}
+	return s.monitorRepo.UpdateColumns(id, map[string]interface{}{
+		"restart_count": nil,
+		"content_hash":  "",
+	})
+}
+
+// RepinHostKey forgets the pinned SSH host key, the next check pins the key
+// the server presents.
+func (s *monitorService) RepinHostKey(id uint) error {
+	existing, err := s.monitorRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("monitor not found")
+	}
+	return s.monitorRepo.UpdateColumns(id, map[string]interface{}{"host_key_fingerprint": ""})
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"uptime_w33d/internal/models"
+	"uptime_w33d/internal/probe"
+	"uptime_w33d/internal/services"
+	"uptime_w33d/pkg/utils"
+)
+
+func TestMonitorService_Acknowledge(t *testing.T) {
+	repo := &memMonitorRepo{monitors: make(map[uint]models.Monitor)}
+	restarts := 3
+	m := models.Monitor{Name: "db", Type: models.TypeDocker, Container: "db", RestartCount: &restarts, ContentHash: "abc"}
+	repo.Create(&m)
+
+	svc := services.NewMonitorService(repo)
+	assert.NoError(t, svc.AcknowledgeMonitor(m.ID))
+
+	stored, _ := repo.GetByID(m.ID)
+	assert.Nil(t, stored.RestartCount)
+	assert.Empty(t, stored.ContentHash)
+
+	assert.Error(t, svc.AcknowledgeMonitor(42))
+}
+
+func TestMonitorService_Update(t *testing.T) {
+	repo := &memMonitorRepo{monitors: make(map[uint]models.Monitor)}
+	m := models.Monitor{
+		Name:               "api",
+		Type:               models.TypeHTTP,
+		Target:             "https://example.com",
+		Assertions:         `[{"source":"status","operator":"==","target":"200"}]`,
+		HostKeyFingerprint: "SHA256:pinned",
+		Password:           "s3cret",
+		ContentHash:        "abc",
+		DetectChanges:      true,
+	}
+	repo.Create(&m)
+	svc := services.NewMonitorService(repo)
+
+	// Secrets and the pinned key are not sent back by clients
+	updates := models.Monitor{Name: "api v2", Type: models.TypeHTTP, Target: "https://example.com", Assertions: m.Assertions, DetectChanges: true}
+	assert.NoError(t, svc.UpdateMonitor(m.ID, &updates))
+
+	stored, _ := repo.GetByID(m.ID)
+	assert.Equal(t, "api v2", stored.Name)
+	assert.Equal(t, m.Assertions, stored.Assertions)
+	assertions, err := probe.ParseAssertions(stored.Assertions)
+	assert.NoError(t, err)
+	assert.Equal(t, []probe.Assertion{{Source: "status", Operator: "==", Target: "200"}}, assertions)
+	assert.Equal(t, "abc", stored.ContentHash)
+	assert.Equal(t, "SHA256:pinned", stored.HostKeyFingerprint)
+	assert.Equal(t, "s3cret", stored.Password)
+
+	// Changing what is hashed drops the baseline
+	updates.Target = "https://example.org"
+	assert.NoError(t, svc.UpdateMonitor(m.ID, &updates))
+	stored, _ = repo.GetByID(m.ID)
+	assert.Equal(t, "https://example.org", stored.Target)
+	assert.Empty(t, stored.ContentHash)
+
+	// Switching to an unencrypted key needs the old passphrase gone
+	updates.ClearPassword = true
+	assert.NoError(t, svc.UpdateMonitor(m.ID, &updates))
+	stored, _ = repo.GetByID(m.ID)
+	assert.Empty(t, stored.Password)
+
+	assert.NoError(t, svc.RepinHostKey(m.ID))
+	stored, _ = repo.GetByID(m.ID)
+	assert.Empty(t, stored.HostKeyFingerprint)
+}
+
+func TestMonitorService_UpdateRedactedTarget(t *testing.T) {
+	repo := &memMonitorRepo{monitors: make(map[uint]models.Monitor)}
+	m := models.Monitor{Name: "db", Type: models.TypePostgres, Target: "postgres://app:s3cret@db:5432/app"}
+	repo.Create(&m)
+	svc := services.NewMonitorService(repo)
+
+	// Clients only ever see the masked DSN
+	redacted := utils.RedactTarget(m.Target)
+	assert.Equal(t, "postgres://app:xxxxx@db:5432/app", redacted)
+	assert.Equal(t, "host=db password=xxxxx user=app", utils.RedactTarget("host=db password='s3 cret' user=app"))
+	assert.Equal(t, "app:xxxxx@tcp(db:3306)/app", utils.RedactTarget("app:s3cret@tcp(db:3306)/app"))
+	assert.Equal(t, "db:5432", utils.RedactTarget("db:5432"))
+
+	assert.NoError(t, svc.UpdateMonitor(m.ID, &models.Monitor{Name: "db", Type: models.TypePostgres, Target: redacted}))
+	stored, _ := repo.GetByID(m.ID)
+	assert.Equal(t, m.Target, stored.Target)
+
+	assert.NoError(t, svc.UpdateMonitor(m.ID, &models.Monitor{Name: "db", Type: models.TypePostgres, Target: "postgres://app:n3w@db:5432/app"}))
+	stored, _ = repo.GetByID(m.ID)
+	assert.Equal(t, "postgres://app:n3w@db:5432/app", stored.Target)
+}
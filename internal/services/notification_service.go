@@ -9,6 +9,7 @@ import (
 	"uptime_w33d/internal/notification"
 	"uptime_w33d/internal/repository"
 	"uptime_w33d/pkg/logger"
+	"uptime_w33d/pkg/utils"
 )
 
 type NotificationService interface {
@@ -54,7 +55,7 @@ func (s *notificationService) Notify(monitor models.Monitor, newStatus string, m
 
 	msg := notification.NotificationMessage{
 		MonitorName: monitor.Name,
-		Target:      monitor.Target,
+		Target:      utils.RedactTarget(monitor.Target),
 		Status:      newStatus,
 		Message:     message,
 		Time:        time.Now().Format(time.RFC3339),
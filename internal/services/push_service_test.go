@@ -36,6 +36,9 @@ func (m *MockMonitorRepo) Create(monitor *models.Monitor) error { return nil }
 func (m *MockMonitorRepo) GetByID(id uint) (*models.Monitor, error) { return nil, nil }
 func (m *MockMonitorRepo) GetAll(userID uint) ([]models.Monitor, error) { return nil, nil }
 func (m *MockMonitorRepo) Delete(id uint) error { return nil }
+func (m *MockMonitorRepo) UpdateColumns(id uint, values map[string]interface{}) error { return nil }
+func (m *MockMonitorRepo) PinBaseline(id uint, target string, column string, value string) error { return nil }
+func (m *MockMonitorRepo) PinRestartCount(id uint, target string, count int) error { return nil }
 
 
 type MockResultRepo struct {
@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 
+	"gorm.io/gorm"
+
 	"uptime_w33d/internal/models"
 	"uptime_w33d/internal/repository"
 )
@@ -15,6 +17,7 @@ type StatusPageService interface {
 	ListStatusPages() ([]models.StatusPage, error)
 	UpdateStatusPage(id uint, page *models.StatusPage, monitorIDs []uint) error
 	DeleteStatusPage(id uint) error
+	IsMonitorPublic(monitor *models.Monitor) (bool, error)
 }
 
 type statusPageService struct {
@@ -89,3 +92,20 @@ func (s *statusPageService) UpdateStatusPage(id uint, updates *models.StatusPage
 func (s *statusPageService) DeleteStatusPage(id uint) error {
 	return s.repo.Delete(id)
 }
+
+// IsMonitorPublic reports whether the monitor may be shown on the public
+// status endpoints: it is marked public, listed on a public status page, or
+// no "default" page exists and the fallback page shows every monitor.
+func (s *statusPageService) IsMonitorPublic(monitor *models.Monitor) (bool, error) {
+	if monitor.IsPublic {
+		return true, nil
+	}
+	listed, err := s.repo.HasMonitor(monitor.ID)
+	if err != nil || listed {
+		return listed, err
+	}
+	if _, err := s.repo.GetBySlug("default"); err != nil {
+		return errors.Is(err, gorm.ErrRecordNotFound), nil
+	}
+	return false, nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var dsnPassword = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactTarget masks a password embedded in a monitor target: URL userinfo
+// (postgres://u:pw@host/db), key=value DSNs (password=pw) and MySQL DSNs
+// (u:pw@tcp(host)/db). Other targets are returned unchanged.
+func RedactTarget(target string) string {
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil || u.User == nil {
+			return target
+		}
+		if _, ok := u.User.Password(); !ok {
+			return target
+		}
+		return u.Redacted()
+	}
+	if dsnPassword.MatchString(target) {
+		return dsnPassword.ReplaceAllString(target, "${1}xxxxx")
+	}
+	if at := strings.LastIndex(target, "@"); at > 0 {
+		if user, _, ok := strings.Cut(target[:at], ":"); ok {
+			return user + ":xxxxx" + target[at:]
+		}
+	}
+	return target
+}
@@ -9,13 +9,28 @@ import {
   Button, IconButton, Dialog, DialogTitle, DialogContent, DialogActions, TextField, 
   MenuItem, Stack, Box, Avatar, Switch, FormControlLabel
 } from '@mui/material';
-import { Plus, Pencil, Trash2, Globe, Server, Activity, Radio, Copy, Gamepad2, Container, Shield } from 'lucide-react';
+import { Plus, Pencil, Trash2, Globe, Server, Activity, Radio, Copy, Gamepad2, Container, Shield, Check, KeyRound } from 'lucide-react';
 
 // --- Types & Schema ---
 
+// Types whose settings the form below can edit
+const formTypes: Record<string, string> = {
+  http: 'HTTP(s) - Website / API',
+  http_keyword: 'HTTP(s) - Keyword Check',
+  http_json: 'HTTP(s) - JSON Query',
+  ws: 'WebSocket (ws/wss)',
+  steam: 'Steam Game Server',
+  docker: 'Docker Container',
+  tcp: 'TCP - Port Check',
+  ping: 'Ping - Server Reachability',
+  dns: 'DNS - Resolve Check',
+  push: 'Push - Heartbeat',
+};
+
 const monitorSchema = z.object({
   name: z.string().min(1, 'Name is required'),
-  type: z.enum(['http', 'http_keyword', 'http_json', 'tcp', 'ws', 'steam', 'docker', 'ping', 'dns', 'push']),
+  // Other types (created through the API) can be edited but not chosen here
+  type: z.string().min(1),
   target: z.string().optional(),
   interval: z.coerce.number().min(10, 'Minimum interval is 10s'),
   timeout: z.coerce.number().min(1),
@@ -25,10 +40,15 @@ const monitorSchema = z.object({
   headers: z.string().optional(),
   body: z.string().optional(),
   keyword: z.string().optional(),
+  container: z.string().optional(),
   json_path: z.string().optional(),
   json_value: z.string().optional(),
   group_id: z.number().optional(),
   enabled: z.boolean().default(true),
+}).superRefine((data, ctx) => {
+  if (data.type === 'docker' && !data.container) {
+    ctx.addIssue({ code: 'custom', path: ['container'], message: 'Container name or ID is required' });
+  }
 });
 
 type MonitorForm = z.infer<typeof monitorSchema>;
@@ -39,6 +59,7 @@ interface Monitor extends MonitorForm {
   enabled: boolean;
   push_token?: string;
   group_id?: number;
+  detect_changes?: boolean;
 }
 
 interface MonitorGroup {
@@ -107,6 +128,16 @@ export default function Monitors() {
     onSuccess: () => queryClient.invalidateQueries({ queryKey: ['monitors'] }),
   });
 
+  const repinMutation = useMutation({
+    mutationFn: (id: number) => api.post(`/monitors/${id}/repin`),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: ['monitors'] }),
+  });
+
+  const acknowledgeMutation = useMutation({
+    mutationFn: (id: number) => api.post(`/monitors/${id}/acknowledge`),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: ['monitors'] }),
+  });
+
   const { control, handleSubmit, reset, setValue, watch } = useForm<MonitorForm>({
     resolver: zodResolver(monitorSchema) as any,
     defaultValues: {
@@ -121,6 +152,7 @@ export default function Monitors() {
       headers: '',
       body: '',
       keyword: '',
+      container: '',
       json_path: '',
       json_value: '',
     },
@@ -142,6 +174,7 @@ export default function Monitors() {
       setValue('headers', (monitor as any).headers || '');
       setValue('body', (monitor as any).body || '');
       setValue('keyword', (monitor as any).keyword || '');
+      setValue('container', (monitor as any).container || (monitor.type === 'docker' ? (monitor as any).keyword : '') || '');
       setValue('json_path', (monitor as any).json_path || '');
       setValue('json_value', (monitor as any).json_value || '');
       setValue('group_id', monitor.group_id);
@@ -160,6 +193,7 @@ export default function Monitors() {
         headers: '',
         body: '',
         keyword: '',
+        container: '',
         json_path: '',
         json_value: '',
         group_id: undefined,
@@ -177,7 +211,9 @@ export default function Monitors() {
 
   const onSubmit = (data: MonitorForm) => {
     if (editingId) {
-      updateMutation.mutate({ id: editingId, data });
+      // Updates replace every setting, keep the ones this form doesn't show
+      const original = monitors?.find((m) => m.id === editingId);
+      updateMutation.mutate({ id: editingId, data: { ...original, ...data } });
     } else {
       createMutation.mutate(data);
     }
@@ -251,7 +287,7 @@ export default function Monitors() {
                 <TableCell>
                   <Chip 
                     label={monitor.last_status || 'Unknown'} 
-                    color={monitor.last_status === 'up' ? 'success' : monitor.last_status === 'down' ? 'error' : 'default'} 
+                    color={monitor.last_status === 'up' ? 'success' : monitor.last_status === 'degraded' ? 'warning' : monitor.last_status === 'down' ? 'error' : 'default'} 
                     size="small"
                     sx={{ fontWeight: 600, minWidth: 80 }}
                   />
@@ -269,6 +305,18 @@ export default function Monitors() {
                 </TableCell>
                 <TableCell align="right" sx={{ pr: 3 }}>
                   <Stack direction="row" justifyContent="flex-end" spacing={1}>
+                    {(monitor.type === 'docker' || monitor.detect_changes) && monitor.last_status === 'down' && (
+                      <IconButton size="small" title="Acknowledge" onClick={() => acknowledgeMutation.mutate(monitor.id)} sx={{ color: 'text.secondary' }}>
+                        <Check size={18} />
+                      </IconButton>
+                    )}
+                    {monitor.type === 'ssh' && (
+                      <IconButton size="small" title="Re-pin host key" onClick={() => {
+                        if (confirm('Trust the host key the server presents on the next check?')) repinMutation.mutate(monitor.id);
+                      }} sx={{ color: 'text.secondary' }}>
+                        <KeyRound size={18} />
+                      </IconButton>
+                    )}
                     <IconButton size="small" onClick={() => handleBadge(monitor)} sx={{ color: 'text.secondary' }}>
                       <Shield size={18} />
                     </IconButton>
@@ -382,16 +430,12 @@ export default function Monitors() {
                 control={control}
                 render={({ field }) => (
                   <TextField {...field} select label="Monitor Type" fullWidth>
-                    <MenuItem value="http">HTTP(s) - Website / API</MenuItem>
-                    <MenuItem value="http_keyword">HTTP(s) - Keyword Check</MenuItem>
-                    <MenuItem value="http_json">HTTP(s) - JSON Query</MenuItem>
-                    <MenuItem value="ws">WebSocket (ws/wss)</MenuItem>
-                    <MenuItem value="steam">Steam Game Server</MenuItem>
-                    <MenuItem value="docker">Docker Container</MenuItem>
-                    <MenuItem value="tcp">TCP - Port Check</MenuItem>
-                    <MenuItem value="ping">Ping - Server Reachability</MenuItem>
-                    <MenuItem value="dns">DNS - Resolve Check</MenuItem>
-                    <MenuItem value="push">Push - Heartbeat</MenuItem>
+                    {Object.entries(formTypes).map(([value, label]) => (
+                      <MenuItem key={value} value={value}>{label}</MenuItem>
+                    ))}
+                    {field.value && !formTypes[field.value] && (
+                      <MenuItem value={field.value} disabled>{field.value}</MenuItem>
+                    )}
                   </TextField>
                 )}
               />
@@ -412,6 +456,23 @@ export default function Monitors() {
                />
               )}
              
+              {type === 'docker' && (
+                <Controller
+                  name="container"
+                  control={control}
+                  render={({ field, fieldState }) => (
+                    <TextField 
+                      {...field} 
+                      label="Container Name / ID" 
+                      placeholder="e.g. postgres" 
+                      fullWidth 
+                      error={!!fieldState.error} 
+                      helperText={fieldState.error?.message || 'Monitor will be DOWN if the container is stopped, unhealthy or restarted'}
+                    />
+                  )}
+                />
+              )}
+
               {type === 'http_keyword' && (
                 <Controller
                   name="keyword"